@@ -0,0 +1,154 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"msLotto/model"
+)
+
+// BacktestPeriod is one replayed recommendation: the games the ranking put
+// on top at one run, and how they and the rest of the field fared by a
+// later run.
+type BacktestPeriod struct {
+	At, Later time.Time
+	Picks     []string // recommended games, best first
+	// Return per $1 at the later run, averaged over the picks and over
+	// every game ranked at At that the later run has, and how much each
+	// moved since At.
+	PickReturn, FieldReturn float64
+	PickChange, FieldChange float64
+	// Picks no longer listed at the later run. Those whose final prize
+	// counts were recorded still count toward the returns, as of then.
+	Ended int
+}
+
+// Beat reports whether the picks returned more per dollar than the field.
+func (p BacktestPeriod) Beat() bool {
+	return p.PickReturn > p.FieldReturn
+}
+
+// BacktestOptions configure Backtest.
+type BacktestOptions struct {
+	Sort    string        // SortBy key the recommendation ranks by
+	Top     int           // games recommended each period
+	Step    time.Duration // time between recommendations
+	Horizon time.Duration // how long after each recommendation it is judged
+}
+
+// Backtest replays the recorded runs, oldest first: every Step it ranks the
+// games the latest run saw and takes the Top, then measures them against
+// the field at the latest run Horizon later. load reads back a run's games.
+func Backtest(runs []time.Time, load func(time.Time) ([]model.Game, error), opts BacktestOptions) ([]BacktestPeriod, error) {
+	if len(runs) == 0 {
+		return nil, nil
+	}
+	// asOf is the latest run at or before t.
+	asOf := func(t time.Time) int {
+		return sort.Search(len(runs), func(i int) bool { return runs[i].After(t) }) - 1
+	}
+
+	var periods []BacktestPeriod
+	last := runs[len(runs)-1]
+	for t := runs[0]; !t.Add(opts.Horizon).After(last); t = t.Add(opts.Step) {
+		i, j := asOf(t), asOf(t.Add(opts.Horizon))
+		if i == j {
+			continue // no run since the recommendation to judge it by
+		}
+		games, err := load(runs[i])
+		if err != nil {
+			return nil, err
+		}
+		later, err := load(runs[j])
+		if err != nil {
+			return nil, err
+		}
+		games = Listed(games)
+		if err := SortBy(games, opts.Sort, nil); err != nil {
+			return nil, err
+		}
+		periods = append(periods, judge(games, later, opts.Top, runs[i], runs[j]))
+	}
+	return periods, nil
+}
+
+// judge compares the top picks from ranked with the field in later.
+func judge(ranked, later []model.Game, top int, at, laterAt time.Time) BacktestPeriod {
+	p := BacktestPeriod{At: at, Later: laterAt}
+	then := map[string]model.Game{}
+	for _, g := range ranked {
+		then[GameKey(g)] = g
+	}
+	now := map[string]model.Game{}
+	var fieldReturn, fieldChange float64
+	var field int
+	for _, g := range later {
+		now[GameKey(g)] = g
+		if before, ok := then[GameKey(g)]; ok {
+			fieldReturn += ReturnPerDollar(g.Price, g.EV())
+			fieldChange += ReturnPerDollar(g.Price, g.EV()) - ReturnPerDollar(before.Price, before.EV())
+			field++
+		}
+	}
+	if field > 0 {
+		p.FieldReturn, p.FieldChange = fieldReturn/float64(field), fieldChange/float64(field)
+	}
+
+	var picked int
+	live, _ := SplitDead(ranked)
+	for _, g := range live[:min(top, len(live))] {
+		p.Picks = append(p.Picks, g.Name)
+		after, ok := now[GameKey(g)]
+		if !ok || !after.Ended.IsZero() {
+			p.Ended++
+		}
+		if !ok {
+			continue
+		}
+		p.PickReturn += ReturnPerDollar(after.Price, after.EV())
+		p.PickChange += ReturnPerDollar(after.Price, after.EV()) - ReturnPerDollar(g.Price, g.EV())
+		picked++
+	}
+	if picked > 0 {
+		p.PickReturn /= float64(picked)
+		p.PickChange /= float64(picked)
+	}
+	return p
+}
+
+// WriteBacktest prints a row per period and how often the picks beat the
+// field.
+func WriteBacktest(w io.Writer, periods []BacktestPeriod, opts BacktestOptions) error {
+	if len(periods) == 0 {
+		fmt.Fprintf(w, "Not enough history to judge recommendations %s later.\n", formatDays(opts.Horizon))
+		return nil
+	}
+	fmt.Fprintf(w, "Top %d by %q, judged %s later by return per $1:\n", opts.Top, opts.Sort, formatDays(opts.Horizon))
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Picked\tJudged\tPicks\tField\tPicks Moved\tField Moved\tEnded\tRecommended")
+	var beat int
+	var pickSum, fieldSum float64
+	for _, p := range periods {
+		if p.Beat() {
+			beat++
+		}
+		pickSum += p.PickReturn
+		fieldSum += p.FieldReturn
+		fmt.Fprintf(tw, "%s\t%s\t%.4f\t%.4f\t%+.4f\t%+.4f\t%d\t%s\n", p.At.Local().Format("2006-01-02"), p.Later.Local().Format("2006-01-02"),
+			p.PickReturn, p.FieldReturn, p.PickChange, p.FieldChange, p.Ended, strings.Join(p.Picks, ", "))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	n := float64(len(periods))
+	fmt.Fprintf(w, "Picks beat the field in %d of %d periods; average return per $1 %.4f vs %.4f.\n", beat, len(periods), pickSum/n, fieldSum/n)
+	return nil
+}
+
+func formatDays(d time.Duration) string {
+	return fmt.Sprintf("%g days", d.Hours()/24)
+}
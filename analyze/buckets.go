@@ -0,0 +1,78 @@
+package analyze
+
+import (
+	"fmt"
+	"strings"
+
+	"msLotto/model"
+)
+
+// PrizeBucket is a range of prize values, from Min up to but not including
+// Max. Max is 0 for the open-ended top bucket.
+type PrizeBucket struct {
+	Label    string
+	Min, Max float64
+}
+
+// PrizeBuckets split prizes by what they pay, smallest first.
+var PrizeBuckets = []PrizeBucket{
+	{Label: "under $100", Min: 0, Max: 100},
+	{Label: "$100-$999", Min: 100, Max: 1000},
+	{Label: "$1,000-$9,999", Min: 1000, Max: 10000},
+	{Label: "$10,000+", Min: 10000},
+}
+
+// Contains reports whether a prize worth v falls in the bucket.
+func (b PrizeBucket) Contains(v float64) bool {
+	return v >= b.Min && (b.Max == 0 || v < b.Max)
+}
+
+// BucketTotal is how many prizes are left in a bucket and what they pay.
+type BucketTotal struct {
+	Bucket PrizeBucket
+	Prizes int
+	Money  float64
+	Share  float64 // of all the remaining prize money counted, 0-1
+}
+
+// RemainingByBucket totals the unclaimed prizes of games, one game or the
+// whole market, into PrizeBuckets, valuing each by its cash value.
+func RemainingByBucket(games ...model.Game) []BucketTotal {
+	totals := make([]BucketTotal, len(PrizeBuckets))
+	for i, b := range PrizeBuckets {
+		totals[i].Bucket = b
+	}
+	var money float64
+	for _, g := range games {
+		for _, p := range g.PrizeTiers {
+			v := g.TierValue(p)
+			if p.RemainingCount <= 0 || v <= 0 {
+				continue
+			}
+			for i, b := range PrizeBuckets {
+				if b.Contains(v) {
+					totals[i].Prizes += p.RemainingCount
+					totals[i].Money += v * float64(p.RemainingCount)
+					money += v * float64(p.RemainingCount)
+					break
+				}
+			}
+		}
+	}
+	if money > 0 {
+		for i := range totals {
+			totals[i].Share = totals[i].Money / money
+		}
+	}
+	return totals
+}
+
+// FormatBuckets is one line of where the remaining prize money sits, e.g.
+// "42% under $100, 31% $100-$999, ...".
+func FormatBuckets(totals []BucketTotal) string {
+	parts := make([]string, len(totals))
+	for i, t := range totals {
+		parts[i] = fmt.Sprintf("%.0f%% %s", 100*t.Share, t.Bucket.Label)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,147 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"msLotto/model"
+)
+
+// TierClaim is how many prizes of one tier were claimed between two runs.
+type TierClaim struct {
+	Value   int
+	Tag     string
+	Claimed int
+	Left    int
+}
+
+// GameChange is one game present in both runs whose EV or prizes moved.
+type GameChange struct {
+	Old, New model.Game
+	Claims   []TierClaim
+}
+
+// EVDelta is how the expected loss moved; negative means the game got better.
+func (c GameChange) EVDelta() float64 {
+	return model.Round(c.New.EV(), 2) - model.Round(c.Old.EV(), 2)
+}
+
+// RunDiff is what changed from one run to the next.
+type RunDiff struct {
+	Added   []model.Game
+	Removed []model.Game // with their final prize counts and Ended set, when the newer run recorded them
+	Changed []GameChange
+}
+
+// GameKey identifies a game across runs by number, or URL for pages without one.
+func GameKey(g model.Game) string {
+	if g.GameNumber != 0 {
+		return strconv.Itoa(g.GameNumber)
+	}
+	return g.URL
+}
+
+// DiffRuns compares two runs' games. Tiers are matched by prize and tag, in
+// page order when a game lists the same prize twice. Games that had ended
+// by the older run are left out.
+func DiffRuns(old, new []model.Game) RunDiff {
+	var d RunDiff
+	old = Listed(old)
+	before := map[string]model.Game{}
+	for _, g := range old {
+		before[GameKey(g)] = g
+	}
+	seen := map[string]bool{}
+	for _, g := range new {
+		k := GameKey(g)
+		prev, ok := before[k]
+		if !g.Ended.IsZero() {
+			if ok {
+				d.Removed = append(d.Removed, g)
+				seen[k] = true
+			}
+			continue
+		}
+		seen[k] = true
+		if !ok {
+			d.Added = append(d.Added, g)
+			continue
+		}
+		c := GameChange{Old: prev, New: g, Claims: tierClaims(prev, g)}
+		if len(c.Claims) > 0 || c.EVDelta() != 0 {
+			d.Changed = append(d.Changed, c)
+		}
+	}
+	for _, g := range old {
+		if !seen[GameKey(g)] {
+			d.Removed = append(d.Removed, g)
+		}
+	}
+	return d
+}
+
+func tierClaims(old, new model.Game) []TierClaim {
+	type key struct {
+		value int
+		tag   string
+		nth   int // repeats of the same prize and tag
+	}
+	keyed := func(tiers []model.PrizeTier) map[key]model.PrizeTier {
+		m := map[key]model.PrizeTier{}
+		for _, p := range tiers {
+			k := key{value: p.Value, tag: p.Tag}
+			for _, dup := m[k]; dup; _, dup = m[k] {
+				k.nth++
+			}
+			m[k] = p
+		}
+		return m
+	}
+	prev := keyed(old.PrizeTiers)
+	var claims []TierClaim
+	for k, p := range keyed(new.PrizeTiers) {
+		if q, ok := prev[k]; ok && q.RemainingCount > p.RemainingCount {
+			claims = append(claims, TierClaim{Value: p.Value, Tag: p.Tag, Claimed: q.RemainingCount - p.RemainingCount, Left: p.RemainingCount})
+		}
+	}
+	sort.Slice(claims, func(i, j int) bool {
+		if claims[i].Value != claims[j].Value {
+			return claims[i].Value > claims[j].Value
+		}
+		return claims[i].Tag < claims[j].Tag
+	})
+	return claims
+}
+
+// WriteRunDiff prints the games that appeared, vanished and changed.
+func WriteRunDiff(w io.Writer, d RunDiff, from, to time.Time) {
+	fmt.Fprintf(w, "Changes from %s to %s\n", from.Local().Format("2006-01-02 15:04"), to.Local().Format("2006-01-02 15:04"))
+	if len(d.Added)+len(d.Removed)+len(d.Changed) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+	for _, g := range d.Added {
+		fmt.Fprintf(w, "+ %s ($%d) new, EV %.2f\n", g.Name, g.Price, g.ReportedEV())
+	}
+	for _, g := range d.Removed {
+		if g.Ended.IsZero() {
+			fmt.Fprintf(w, "- %s ($%d) no longer listed\n", g.Name, g.Price)
+		} else {
+			fmt.Fprintf(w, "- %s ($%d) ended with %d of %d prizes left\n", g.Name, g.Price, g.TotalRemainingPrizes, g.TotalOriginalPrizes)
+		}
+	}
+	for _, c := range d.Changed {
+		delta := c.New.EVSign().Apply(c.EVDelta())
+		fmt.Fprintf(w, "~ %s ($%d) EV %.2f -> %.2f (%+.2f)\n", c.New.Name, c.New.Price, c.Old.ReportedEV(), c.New.ReportedEV(), delta)
+		for _, cl := range c.Claims {
+			label := "$" + strconv.Itoa(cl.Value)
+			if cl.Tag != "" {
+				label += " " + cl.Tag
+			}
+			fmt.Fprintf(w, "    %-16s %d claimed, %d left\n", label, cl.Claimed, cl.Left)
+		}
+	}
+}
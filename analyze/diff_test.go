@@ -0,0 +1,28 @@
+package analyze
+
+import (
+	"testing"
+	"time"
+
+	"msLotto/model"
+)
+
+func TestDiffRunsEnded(t *testing.T) {
+	game := func(number, remaining int) model.Game {
+		return model.Game{Name: "Game", Price: 1, GameNumber: number, TotalRemainingPrizes: remaining,
+			PrizeTiers: []model.PrizeTier{{Value: 1, OriginalCount: 10, RemainingCount: remaining}}}
+	}
+	endedAt := time.Date(2025, 3, 2, 0, 0, 0, 0, time.UTC)
+	ended := func(g model.Game) model.Game { g.Ended = endedAt; return g }
+
+	// Game 2 ends between the runs; game 3 had ended before either.
+	old := []model.Game{game(1, 5), game(2, 5), ended(game(3, 1))}
+	new := []model.Game{game(1, 5), ended(game(2, 4)), ended(game(3, 1))}
+	d := DiffRuns(old, new)
+	if len(d.Added) != 0 || len(d.Changed) != 0 {
+		t.Errorf("DiffRuns added %+v and changed %+v, want neither", d.Added, d.Changed)
+	}
+	if len(d.Removed) != 1 || d.Removed[0].GameNumber != 2 || d.Removed[0].TotalRemainingPrizes != 4 {
+		t.Errorf("DiffRuns removed %+v, want game 2 with its final counts", d.Removed)
+	}
+}
@@ -0,0 +1,142 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"msLotto/model"
+)
+
+// DrawPrize is what matching White main balls, plus the special ball when
+// Special is set, pays. A zero Prize is the jackpot.
+type DrawPrize struct {
+	White   int
+	Special bool
+	Prize   float64
+}
+
+// DrawGame is a multi-state draw game: pick 5 of WhiteBalls and 1 of
+// SpecialBalls. Odds come from the matrix rather than published tables.
+type DrawGame struct {
+	Name         string
+	Price        int
+	WhiteBalls   int
+	SpecialBalls int
+	Prizes       []DrawPrize
+	// Multiplier is the average built-in multiplier applied to every
+	// non-jackpot prize, 1 for games without one.
+	Multiplier float64
+}
+
+// Powerball is the $2 game without Power Play.
+var Powerball = DrawGame{
+	Name: "Powerball", Price: 2, WhiteBalls: 69, SpecialBalls: 26, Multiplier: 1,
+	Prizes: []DrawPrize{
+		{5, true, 0}, {5, false, 1_000_000}, {4, true, 50_000}, {4, false, 100},
+		{3, true, 100}, {3, false, 7}, {2, true, 7}, {1, true, 4}, {0, true, 4},
+	},
+}
+
+// MegaMillions is the $5 game from April 2025, whose base prizes are
+// multiplied by 2x to 10x; 2.95 is the average multiplier from its published
+// multiplier odds.
+var MegaMillions = DrawGame{
+	Name: "Mega Millions", Price: 5, WhiteBalls: 70, SpecialBalls: 24, Multiplier: 2.95,
+	Prizes: []DrawPrize{
+		{5, true, 0}, {5, false, 1_000_000}, {4, true, 10_000}, {4, false, 500},
+		{3, true, 200}, {3, false, 10}, {2, true, 10}, {1, true, 7}, {0, true, 5},
+	},
+}
+
+func choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	r := 1.0
+	for i := 0; i < k; i++ {
+		r = r * float64(n-i) / float64(i+1)
+	}
+	return r
+}
+
+// Chance is the probability of a ticket matching exactly white main balls
+// and, with special, the special ball.
+func (d DrawGame) Chance(white int, special bool) float64 {
+	p := choose(5, white) * choose(d.WhiteBalls-5, 5-white) / choose(d.WhiteBalls, 5)
+	if special {
+		return p / float64(d.SpecialBalls)
+	}
+	return p * float64(d.SpecialBalls-1) / float64(d.SpecialBalls)
+}
+
+// EV is the expected loss of one ticket, with the same sign as Game.EV, for
+// a jackpot paid out as the given cash amount. It assumes a winning jackpot
+// isn't shared, so it flatters the game when ticket sales are high.
+func (d DrawGame) EV(jackpotCash float64) float64 {
+	var win float64
+	for _, p := range d.Prizes {
+		prize := p.Prize * d.Multiplier
+		if p.Prize == 0 {
+			prize = jackpotCash
+		}
+		win += d.Chance(p.White, p.Special) * prize
+	}
+	return float64(d.Price) - win
+}
+
+// DrawComparison is one draw game's EV at this week's jackpot.
+type DrawComparison struct {
+	Game    DrawGame
+	Jackpot float64 // advertised
+	Cash    float64 // lump sum the EV is based on
+	EV      float64
+}
+
+// CompareDraw works out each draw game's EV, given advertised jackpots by
+// game name and the share of the advertised jackpot paid as a lump sum.
+func CompareDraw(jackpots map[string]float64, cashShare float64) []DrawComparison {
+	var out []DrawComparison
+	for _, d := range []DrawGame{Powerball, MegaMillions} {
+		j, ok := jackpots[d.Name]
+		if !ok {
+			continue
+		}
+		cash := j * cashShare
+		out = append(out, DrawComparison{Game: d, Jackpot: j, Cash: cash, EV: d.EV(cash)})
+	}
+	return out
+}
+
+// FormatDrawComparison lines the draw games up against the best scratch-off
+// by return per dollar.
+func FormatDrawComparison(draws []DrawComparison, games []model.Game) string {
+	var b strings.Builder
+	sign := model.NetLoss // the draw games report EV the way the scratch-offs do
+	if len(games) > 0 {
+		sign = games[0].EVSign()
+	}
+	for _, c := range draws {
+		fmt.Fprintf(&b, "%s ($%d, $%s jackpot): EV %.2f, returns $%.2f per $1\n",
+			c.Game.Name, c.Game.Price, millions(c.Jackpot), sign.Apply(c.EV), ReturnPerDollar(c.Game.Price, c.EV))
+	}
+	if g, r, ok := BestReturn(games); ok {
+		fmt.Fprintf(&b, "Best scratch-off: %s ($%d): EV %.2f, returns $%.2f per $1\n",
+			g.Name, g.Price, g.ReportedEV(), r)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func millions(v float64) string {
+	if v >= 1e9 {
+		return fmt.Sprintf("%.2fB", v/1e9)
+	}
+	return fmt.Sprintf("%.0fM", math.Round(v/1e6))
+}
+
+// WriteDrawComparison prints the comparison with a heading.
+func WriteDrawComparison(w io.Writer, draws []DrawComparison, games []model.Game) {
+	fmt.Fprintln(w, "Draw games vs. scratch-offs (jackpot EV assumes no split):")
+	fmt.Fprintln(w, FormatDrawComparison(draws, games))
+}
@@ -0,0 +1,62 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"msLotto/model"
+)
+
+// WriteExplanation walks through the arithmetic behind a game's EV.
+func WriteExplanation(w io.Writer, g model.Game) {
+	fmt.Fprintf(w, "Game %d: %s ($%d ticket)\n%s\n\n", g.GameNumber, g.Name, g.Price, g.URL)
+
+	orig := g.Estimator().OriginalTickets(g)
+	remain := g.Estimator().RemainingTickets(g)
+	fmt.Fprintf(w, "Overall odds are 1:%.2f, so every winning ticket stands for %.2f tickets printed.\n", g.Odds, g.Odds)
+	fmt.Fprintf(w, "  Original tickets:  %d winners -> about %d tickets (between %d and %d)\n",
+		g.TotalOriginalPrizes, orig.Value, orig.Low, orig.High)
+	fmt.Fprintf(w, "  Remaining tickets: %d winners -> about %d tickets (between %d and %d)\n\n",
+		g.TotalRemainingPrizes, remain.Value, remain.Low, remain.High)
+
+	if remain.Value == 0 {
+		fmt.Fprintf(w, "No remaining tickets can be estimated, so a ticket is counted as a total loss: EV = $%.2f.\n", g.EV())
+		return
+	}
+
+	fmt.Fprintf(w, "A ticket bought today wins a tier with probability remaining prizes / %d:\n", remain.Value)
+	fmt.Fprintf(w, "  %-16s %10s %14s %13s\n", "Prize", "Remaining", "Chance", "Contribution")
+	var expected float64
+	for _, p := range g.PrizeTiers {
+		v := g.TierValue(p)
+		if p.RemainingCount <= 0 || v <= 0 {
+			continue
+		}
+		prob := float64(p.RemainingCount) / float64(remain.Value)
+		contrib := prob * v
+		expected += contrib
+		label := "$" + strconv.Itoa(p.Value)
+		if p.Tag != "" {
+			label += " " + p.Tag
+		}
+		fmt.Fprintf(w, "  %-16s %10d %14s %12s\n", label, p.RemainingCount,
+			fmt.Sprintf("1 in %.1f", 1/prob), fmt.Sprintf("$%.4f", contrib))
+	}
+	fmt.Fprintf(w, "  %s\n", strings.Repeat("-", 56))
+	fmt.Fprintf(w, "  Expected winnings per ticket: $%.4f\n\n", expected)
+	fmt.Fprintf(w, "EV = price - expected winnings = $%d - $%.4f = $%.2f expected loss per ticket\n", g.Price, expected, g.EV())
+	fmt.Fprintf(w, "That returns $%.2f of every $1 spent.\n", ReturnPerDollar(g.Price, g.EV()))
+}
+
+// FindGame matches a game by number, or by name for pages without one.
+func FindGame(games []model.Game, target string) (model.Game, bool) {
+	n, _ := strconv.Atoi(target)
+	for _, g := range games {
+		if (n != 0 && g.GameNumber == n) || strings.EqualFold(g.Name, target) {
+			return g, true
+		}
+	}
+	return model.Game{}, false
+}
@@ -0,0 +1,212 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"msLotto/model"
+)
+
+// ComputedColumn is a user-defined metric such as "ev_per_dollar = ev / price",
+// evaluated per game and added to every output format.
+type ComputedColumn struct {
+	Name string
+	Expr string
+	eval func(vars map[string]float64) float64
+}
+
+// ParseComputedColumn parses "name=expression". Expressions support numbers,
+// the fields listed by gameVars, + - * /, unary minus and parentheses.
+// Division by zero evaluates to 0 rather than Inf so outputs stay numeric.
+func ParseComputedColumn(def string) (ComputedColumn, error) {
+	name, src, ok := strings.Cut(def, "=")
+	name, src = strings.TrimSpace(name), strings.TrimSpace(src)
+	if !ok || name == "" || src == "" {
+		return ComputedColumn{}, fmt.Errorf("computed column %q: want name=expression", def)
+	}
+	p := &exprParser{src: src}
+	p.next()
+	eval, err := p.parseSum()
+	if err == nil && p.tok != "" {
+		err = fmt.Errorf("unexpected %q", p.tok)
+	}
+	if err != nil {
+		return ComputedColumn{}, fmt.Errorf("computed column %s: %w", name, err)
+	}
+	return ComputedColumn{Name: name, Expr: src, eval: eval}, nil
+}
+
+// Eval computes the column for one game.
+func (c ComputedColumn) Eval(g model.Game) float64 {
+	return c.eval(gameVars(g))
+}
+
+// gameVars are the names a computed column can refer to.
+func gameVars(g model.Game) map[string]float64 {
+	return map[string]float64{
+		"price":                  float64(g.Price),
+		"odds":                   g.Odds,
+		"game_number":            float64(g.GameNumber),
+		"total_original_prizes":  float64(g.TotalOriginalPrizes),
+		"total_remaining_prizes": float64(g.TotalRemainingPrizes),
+		"total_tickets":          float64(g.TotalTickets),
+		"original_tickets":       float64(g.OriginalTickets()),
+		"remaining_tickets":      float64(g.RemainingTickets()),
+		"original_prize_money":   float64(g.OriginalPrizeMoney()),
+		"remaining_prize_money":  float64(g.RemainingPrizeMoney()),
+		"payout_remaining":       g.PayoutRemaining(),
+		"ev":                     g.EV(),
+		"return_per_ticket":      g.ReturnRate(),
+		"annualized_return":      g.AnnualizedReturn(),
+		"profit_chance":          g.ProfitChance(),
+		"top_prizes_left":        float64(g.TopTier().RemainingCount),
+		"ev_without_top_prize":   g.EVWithoutTopPrize(),
+	}
+}
+
+// GameVarNames lists the identifiers available to computed columns.
+func GameVarNames() []string {
+	var names []string
+	for k := range gameVars(model.Game{}) {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type evalFunc = func(vars map[string]float64) float64
+
+// exprParser is a small recursive-descent parser:
+//
+//	sum     = product { ("+" | "-") product }
+//	product = unary { ("*" | "/") unary }
+//	unary   = "-" unary | primary
+//	primary = number | identifier | "(" sum ")"
+type exprParser struct {
+	src string
+	pos int
+	tok string
+}
+
+func (p *exprParser) next() {
+	for p.pos < len(p.src) && p.src[p.pos] == ' ' {
+		p.pos++
+	}
+	if p.pos >= len(p.src) {
+		p.tok = ""
+		return
+	}
+	start := p.pos
+	c := rune(p.src[p.pos])
+	switch {
+	case unicode.IsDigit(c) || c == '.':
+		for p.pos < len(p.src) && (unicode.IsDigit(rune(p.src[p.pos])) || p.src[p.pos] == '.') {
+			p.pos++
+		}
+	case unicode.IsLetter(c) || c == '_':
+		for p.pos < len(p.src) && (unicode.IsLetter(rune(p.src[p.pos])) || unicode.IsDigit(rune(p.src[p.pos])) || p.src[p.pos] == '_') {
+			p.pos++
+		}
+	default:
+		p.pos++
+	}
+	p.tok = p.src[start:p.pos]
+}
+
+func (p *exprParser) parseSum() (evalFunc, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "+" || p.tok == "-" {
+		op := p.tok
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(v map[string]float64) float64 { return l(v) + right(v) }
+		} else {
+			left = func(v map[string]float64) float64 { return l(v) - right(v) }
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseProduct() (evalFunc, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.tok == "*" || p.tok == "/" {
+		op := p.tok
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "*" {
+			left = func(v map[string]float64) float64 { return l(v) * right(v) }
+		} else {
+			left = func(v map[string]float64) float64 {
+				d := right(v)
+				if d == 0 {
+					return 0
+				}
+				return l(v) / d
+			}
+		}
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (evalFunc, error) {
+	if p.tok == "-" {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(v map[string]float64) float64 { return -inner(v) }, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (evalFunc, error) {
+	tok := p.tok
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case tok == "(":
+		p.next()
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok != ")" {
+			return nil, fmt.Errorf("missing )")
+		}
+		p.next()
+		return inner, nil
+	case unicode.IsDigit(rune(tok[0])) || tok[0] == '.':
+		n, err := strconv.ParseFloat(tok, 64)
+		if err != nil {
+			return nil, fmt.Errorf("bad number %q", tok)
+		}
+		p.next()
+		return func(map[string]float64) float64 { return n }, nil
+	case unicode.IsLetter(rune(tok[0])) || tok[0] == '_':
+		if _, ok := gameVars(model.Game{})[tok]; !ok {
+			return nil, fmt.Errorf("unknown field %q (have %s)", tok, strings.Join(GameVarNames(), ", "))
+		}
+		p.next()
+		return func(v map[string]float64) float64 { return v[tok] }, nil
+	}
+	return nil, fmt.Errorf("unexpected %q", tok)
+}
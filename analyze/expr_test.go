@@ -0,0 +1,68 @@
+package analyze
+
+import (
+	"testing"
+
+	"msLotto/model"
+)
+
+func TestComputedColumn(t *testing.T) {
+	g := model.Game{Price: 5, GameNumber: 10}
+	tests := []struct {
+		def  string
+		want float64
+	}{
+		{"a = price * 2 + 1", 11},
+		{"a = 1 + price * 2", 11},
+		{"a = (1 + price) * 2", 12},
+		{"a = -(price - 1) / 2", -2},
+		{"a = --price", 5},
+		{"a = 1 - 2 - 3", -4},
+		{"a = 8 / 2 / 2", 2},
+		{"a = game_number / price", 2},
+		{"a = .5 * price", 2.5},
+		{"a = price / 0", 0},
+		{"a=price", 5},
+	}
+	for _, tt := range tests {
+		c, err := ParseComputedColumn(tt.def)
+		if err != nil {
+			t.Errorf("ParseComputedColumn(%q): %v", tt.def, err)
+			continue
+		}
+		if c.Name != "a" {
+			t.Errorf("ParseComputedColumn(%q) named %q, want a", tt.def, c.Name)
+		}
+		if got := c.Eval(g); got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestComputedColumnErrors(t *testing.T) {
+	for _, def := range []string{
+		"price",
+		"= price",
+		"a =",
+		"a = price +",
+		"a = (price",
+		"a = price)",
+		"a = unknown_field",
+		"a = price price",
+		"a = 1..2",
+		"a = price % 2",
+	} {
+		if _, err := ParseComputedColumn(def); err == nil {
+			t.Errorf("ParseComputedColumn(%q) succeeded", def)
+		}
+	}
+}
+
+func TestGameVarNames(t *testing.T) {
+	// Every advertised name must parse as an expression.
+	for _, name := range GameVarNames() {
+		if _, err := ParseComputedColumn("c = " + name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"text/tabwriter"
+
+	"msLotto/model"
+)
+
+// KellyFraction is the share of a bankroll that maximizes its expected
+// logarithmic growth when spent on g's tickets, treating each prize tier as
+// one outcome of the bet. It is zero whenever a ticket's expected winnings
+// don't exceed its price, and always below 1, since every ticket can lose.
+func KellyFraction(g model.Game) float64 {
+	if g.Price <= 0 {
+		return 0
+	}
+	s := newTicketSampler(g)
+	// Each outcome multiplies the stake by returns[i] with probability probs[i].
+	var probs, returns []float64
+	var prev float64
+	for i, c := range s.cum {
+		probs = append(probs, c-prev)
+		returns = append(returns, s.values[i]/float64(g.Price))
+		prev = c
+	}
+	probs = append(probs, max(1-prev, 0))
+	returns = append(returns, 0)
+
+	// growth is the derivative of the expected log growth at fraction f. It
+	// falls as f rises, so its root is the optimum.
+	growth := func(f float64) float64 {
+		var d float64
+		for i, p := range probs {
+			d += p * (returns[i] - 1) / (1 + f*(returns[i]-1))
+		}
+		return d
+	}
+	if growth(0) <= 0 {
+		return 0
+	}
+	lo, hi := 0.0, 1.0
+	for range 60 {
+		mid := (lo + hi) / 2
+		if growth(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return lo
+}
+
+// Advice is the Kelly sizing for one game and a bankroll.
+type Advice struct {
+	Game      model.Game
+	PerDollar float64 // expected return per $1
+	Fraction  float64 // KellyFraction
+	Stake     float64 // Fraction of the bankroll, in dollars
+	Tickets   int     // whole tickets the stake buys
+}
+
+// Advise sizes a bet on each live game for bankroll dollars, best return
+// per dollar first, keeping the top games (all of them when top is 0).
+func Advise(games []model.Game, bankroll float64, top int) []Advice {
+	live, _ := SplitDead(games)
+	advice := make([]Advice, 0, len(live))
+	for _, g := range live {
+		if g.Price <= 0 {
+			continue
+		}
+		f := KellyFraction(g)
+		a := Advice{Game: g, PerDollar: ReturnPerDollar(g.Price, g.EV()), Fraction: f, Stake: f * bankroll}
+		a.Tickets = int(math.Floor(a.Stake / float64(g.Price)))
+		advice = append(advice, a)
+	}
+	sort.SliceStable(advice, func(i, j int) bool { return advice[i].PerDollar > advice[j].PerDollar })
+	if top > 0 && len(advice) > top {
+		advice = advice[:top]
+	}
+	return advice
+}
+
+// WriteAdvice prints the Kelly sizing for each game. A game whose tickets
+// return less than they cost gets a stake of zero.
+func WriteAdvice(w io.Writer, advice []Advice, bankroll float64) error {
+	fmt.Fprintf(w, "Kelly sizing for a $%.2f bankroll:\n", bankroll)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Game\tPrice\tReturn per $1\tKelly\tStake\tTickets")
+	var positive bool
+	for _, a := range advice {
+		if a.Fraction > 0 {
+			positive = true
+		}
+		fmt.Fprintf(tw, "%s\t$%d\t%.4f\t%.2f%%\t$%.2f\t%d\n", a.Game.Name, a.Game.Price, a.PerDollar, 100*a.Fraction, a.Stake, a.Tickets)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if !positive {
+		fmt.Fprintln(w, "No game returns more than it costs, so Kelly says to bet nothing.")
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package analyze
+
+import (
+	"math"
+	"testing"
+
+	"msLotto/model"
+)
+
+// perDollarGame is a game whose tickets return perDollar on the dollar: one
+// in 100 tickets wins a single prize worth 100*price*perDollar.
+func perDollarGame(name string, price int, perDollar float64) model.Game {
+	prizes := 1000
+	return model.Game{
+		Name:                 name,
+		Price:                price,
+		Odds:                 100,
+		PrizeTiers:           []model.PrizeTier{{Value: int(math.Round(100 * float64(price) * perDollar)), OriginalCount: prizes, RemainingCount: prizes}},
+		TotalOriginalPrizes:  prizes,
+		TotalRemainingPrizes: prizes,
+	}
+}
+
+func TestKellyFraction(t *testing.T) {
+	tests := []struct {
+		name string
+		g    model.Game
+		want float64
+	}{
+		// Even odds paying 2 to 1: f = (bp - q) / b = (2*0.5 - 0.5) / 2.
+		{"favorable coin flip", coinFlip(3), 0.25},
+		// Paying 3 to 1: (3*0.5 - 0.5) / 3.
+		{"better coin flip", coinFlip(4), 1.0 / 3},
+		{"fair coin flip", coinFlip(2), 0},
+		{"losing game", perDollarGame("Losing", 5, 0.7), 0},
+		{"no price", model.Game{}, 0},
+	}
+	for _, tt := range tests {
+		if got := KellyFraction(tt.g); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: KellyFraction = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAdvise(t *testing.T) {
+	dead := coinFlip(10)
+	dead.Name, dead.PrizeTiers[0].RemainingCount, dead.TotalRemainingPrizes = "Dead", 0, 0
+	better := coinFlip(4)
+	better.Name = "Better"
+	games := []model.Game{perDollarGame("Losing", 5, 0.7), coinFlip(3), dead, better}
+
+	advice := Advise(games, 100, 0)
+	var names []string
+	for _, a := range advice {
+		names = append(names, a.Game.Name)
+	}
+	if len(names) != 3 || names[0] != "Better" || names[1] != "Coin Flip" || names[2] != "Losing" {
+		t.Fatalf("Advise listed %v, want Better, Coin Flip, Losing", names)
+	}
+	if a := advice[1]; math.Abs(a.Stake-25) > 1e-6 || a.Tickets != int(math.Floor(a.Stake)) {
+		t.Errorf("Coin Flip advice = %+v, want a $25 stake in $1 tickets", a)
+	}
+	if a := advice[2]; a.Stake != 0 || a.Tickets != 0 {
+		t.Errorf("losing game advice = %+v, want no stake", a)
+	}
+
+	if top := Advise(games, 100, 1); len(top) != 1 || top[0].Game.Name != "Better" {
+		t.Errorf("Advise top 1 = %+v, want Better", top)
+	}
+}
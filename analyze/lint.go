@@ -0,0 +1,49 @@
+package analyze
+
+import (
+	"fmt"
+
+	"msLotto/model"
+)
+
+// Plausible range for expected winnings per dollar spent. Real games pay back
+// roughly 55-80% at launch, and can pass $1 late in their run when the big
+// prizes outlast the tickets, which is what mslotto looks for; anything
+// outside this points at a parsing problem.
+const (
+	minPlausibleReturn = 0.3
+	maxPlausibleReturn = 2.0
+)
+
+// LintGame checks the invariants every parsed game should satisfy and returns
+// one message per violation.
+func LintGame(g model.Game) []string {
+	var problems []string
+	var orig, remain int
+	for _, p := range g.PrizeTiers {
+		if p.RemainingCount > p.OriginalCount {
+			problems = append(problems, fmt.Sprintf("$%d tier has %d remaining of %d original", p.Value, p.RemainingCount, p.OriginalCount))
+		}
+		orig += p.OriginalCount
+		remain += p.RemainingCount
+	}
+	if orig != g.TotalOriginalPrizes {
+		problems = append(problems, fmt.Sprintf("total original prizes %d, tiers sum to %d", g.TotalOriginalPrizes, orig))
+	}
+	if remain != g.TotalRemainingPrizes {
+		problems = append(problems, fmt.Sprintf("total remaining prizes %d, tiers sum to %d", g.TotalRemainingPrizes, remain))
+	}
+	if g.Odds <= 1 {
+		problems = append(problems, fmt.Sprintf("overall odds 1:%.2f, want greater than 1", g.Odds))
+	}
+	if err := model.CheckPrice(g); err != nil {
+		problems = append(problems, fmt.Sprintf("ticket price $%d is not a known price point", g.Price))
+	}
+	if g.Price > 0 && g.RemainingTickets() > 0 {
+		perDollar := ReturnPerDollar(g.Price, g.EV())
+		if perDollar < minPlausibleReturn || perDollar > maxPlausibleReturn {
+			problems = append(problems, fmt.Sprintf("expected return $%.2f per $1 is outside %.2f-%.2f", perDollar, minPlausibleReturn, maxPlausibleReturn))
+		}
+	}
+	return problems
+}
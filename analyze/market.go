@@ -0,0 +1,30 @@
+package analyze
+
+import (
+	"time"
+
+	"msLotto/model"
+)
+
+// MarketPoint is the market's return per dollar as one run saw it.
+type MarketPoint struct {
+	At     time.Time
+	Return float64
+}
+
+// MarketReturn is what the listed games' remaining tickets, taken
+// together, are expected to hand back per dollar they cost: each game's
+// expected winnings weighted by how many of its tickets are left. ok is
+// false when no tickets are left to weigh.
+func MarketReturn(games []model.Game) (ret float64, ok bool) {
+	var won, spent float64
+	for _, g := range Listed(games) {
+		n := float64(g.RemainingTickets())
+		won += (float64(g.Price) - g.EV()) * n
+		spent += float64(g.Price) * n
+	}
+	if spent == 0 {
+		return 0, false
+	}
+	return won / spent, true
+}
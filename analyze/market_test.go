@@ -0,0 +1,24 @@
+package analyze
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"msLotto/model"
+)
+
+func TestMarketReturn(t *testing.T) {
+	ended := perDollarGame("Ended", 1, 2)
+	ended.Ended = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	// The same number of tickets left in each, so the $10 game counts
+	// twice as much as the $5 one: (5*0.6 + 10*0.8) / 15.
+	games := []model.Game{perDollarGame("Five", 5, 0.6), perDollarGame("Ten", 10, 0.8), ended}
+	got, ok := MarketReturn(games)
+	if want := 11.0 / 15; !ok || math.Abs(got-want) > 1e-9 {
+		t.Errorf("MarketReturn = %v, %v; want %v", got, ok, want)
+	}
+	if _, ok := MarketReturn([]model.Game{ended}); ok {
+		t.Error("MarketReturn of ended games only is ok, want no tickets to weigh")
+	}
+}
@@ -0,0 +1,205 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+
+	"msLotto/model"
+)
+
+// Objectives Optimize can maximize.
+const (
+	MaxReturn       = "return" // expected winnings
+	MaxProfitChance = "profit" // chance the tickets win back more than they cost
+)
+
+// OptimizeOptions configure Optimize.
+type OptimizeOptions struct {
+	Budget      int
+	Objective   string
+	Trials      int    // simulated trials per portfolio considered for MaxProfitChance, and for the result
+	Seed        uint64 // seeds the simulations, which all draw the same numbers so portfolios compare fairly
+	IncludeDead bool   // consider games with no prizes left too
+}
+
+// maxOptimizeRounds bounds the MaxProfitChance search.
+const maxOptimizeRounds = 100
+
+// Optimize picks how many tickets of which games to buy for at most the
+// budget. Only the best game by return per dollar at each price point is
+// considered, as in PlanVisit, but the tickets aren't spread greedily:
+// MaxReturn is solved exactly over those games; MaxProfitChance
+// starts from the best single-game and MaxReturn portfolios and trades
+// tickets between games while the simulated chance of profit improves.
+// The returned distribution is the chosen portfolio's simulated winnings.
+func Optimize(games []model.Game, opts OptimizeOptions) ([]PlanItem, Distribution, error) {
+	if opts.Objective != MaxReturn && opts.Objective != MaxProfitChance {
+		return nil, Distribution{}, fmt.Errorf("unknown objective %q (have %s, %s)", opts.Objective, MaxReturn, MaxProfitChance)
+	}
+	picks := planCandidates(games, PlanOptions{Budget: opts.Budget, IncludeDead: opts.IncludeDead})
+	if len(picks) == 0 {
+		return nil, Distribution{}, nil
+	}
+	cands := make([]model.Game, len(picks))
+	samplers := make([]ticketSampler, len(picks))
+	for i, it := range picks {
+		cands[i] = it.Game
+		samplers[i] = newTicketSampler(it.Game)
+	}
+	simulate := func(counts []int) Distribution {
+		return simulateCounts(cands, samplers, counts, opts.Trials, rand.New(rand.NewPCG(opts.Seed, 0)))
+	}
+
+	counts := bestReturn(picks, opts.Budget)
+	if opts.Objective == MaxProfitChance {
+		counts = bestProfitChance(cands, counts, opts.Budget, simulate)
+	}
+
+	// picks is already best return per dollar first.
+	plan := picks[:0]
+	for i, it := range picks {
+		if counts[i] > 0 {
+			it.Quantity = counts[i]
+			plan = append(plan, it)
+		}
+	}
+	return plan, simulate(counts), nil
+}
+
+// bestReturn solves the unbounded knapsack: the ticket counts with the most
+// expected winnings that cost at most budget.
+func bestReturn(picks []PlanItem, budget int) []int {
+	best := make([]float64, budget+1) // best[b] is the most expected winnings for b dollars
+	last := make([]int, budget+1)     // the game whose ticket got there, -1 for none
+	for b := range last {
+		last[b] = -1
+		if b > 0 {
+			best[b], last[b] = best[b-1], -2 // -2: same as b-1
+		}
+		for i, it := range picks {
+			price := it.Game.Price
+			if price > b {
+				continue
+			}
+			if v := best[b-price] + float64(price)*it.PerDollar; v > best[b] {
+				best[b], last[b] = v, i
+			}
+		}
+	}
+	counts := make([]int, len(picks))
+	for b := budget; b > 0; {
+		switch i := last[b]; i {
+		case -1:
+			b = 0
+		case -2:
+			b--
+		default:
+			counts[i]++
+			b -= picks[i].Game.Price
+		}
+	}
+	return counts
+}
+
+// bestProfitChance hill-climbs from the better of start and each
+// single-game portfolio, adding tickets and swapping one game's ticket for
+// another's while that raises the simulated chance of profit.
+func bestProfitChance(cands []model.Game, start []int, budget int, simulate func([]int) Distribution) []int {
+	better := func(a, b Distribution) bool {
+		if a.ProfitChance != b.ProfitChance {
+			return a.ProfitChance > b.ProfitChance
+		}
+		return a.Mean-float64(a.Cost) > b.Mean-float64(b.Cost)
+	}
+	cur, curD := start, simulate(start)
+	for i, g := range cands {
+		single := make([]int, len(cands))
+		single[i] = budget / g.Price
+		if d := simulate(single); better(d, curD) {
+			cur, curD = single, d
+		}
+	}
+
+	spent := func(counts []int) int {
+		var s int
+		for i, n := range counts {
+			s += n * cands[i].Price
+		}
+		return s
+	}
+	for range maxOptimizeRounds {
+		var next []int
+		nextD := curD
+		try := func(counts []int) {
+			if d := simulate(counts); better(d, nextD) {
+				next, nextD = counts, d
+			}
+		}
+		left := budget - spent(cur)
+		for j, g := range cands {
+			if g.Price <= left {
+				c := append([]int(nil), cur...)
+				c[j]++
+				try(c)
+			}
+			for i := range cands {
+				if i == j || cur[i] == 0 {
+					continue
+				}
+				freed := left + cands[i].Price
+				if g.Price > freed {
+					continue
+				}
+				c := append([]int(nil), cur...)
+				c[i]--
+				c[j] += freed / g.Price
+				try(c)
+			}
+		}
+		if next == nil {
+			break
+		}
+		cur, curD = next, nextD
+	}
+	return cur
+}
+
+// simulateCounts buys counts[i] tickets of cands[i] in each trial.
+func simulateCounts(cands []model.Game, samplers []ticketSampler, counts []int, trials int, r *rand.Rand) Distribution {
+	cost := 0
+	for i, g := range cands {
+		cost += counts[i] * g.Price
+	}
+	totals := make([]float64, trials)
+	for t := range totals {
+		var won float64
+		for i, s := range samplers {
+			for range counts[i] {
+				won += s.draw(r)
+			}
+		}
+		totals[t] = won
+	}
+	return summarize(totals, cost)
+}
+
+// WriteOptimize prints the chosen tickets and how their simulated winnings
+// spread.
+func WriteOptimize(w io.Writer, plan []PlanItem, budget int, objective string, d Distribution) {
+	goal := "expected return"
+	if objective == MaxProfitChance {
+		goal = "chance of profit"
+	}
+	fmt.Fprintf(w, "Best %s for $%d:\n", goal, budget)
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "No games fit the budget.")
+		return
+	}
+	fmt.Fprintln(w, FormatPlan(plan, budget))
+	fmt.Fprintf(w, "  Simulated mean:   $%.2f over %d trials\n", d.Mean, d.Trials)
+	fmt.Fprintf(w, "  Median:           $%.2f\n", d.Median)
+	fmt.Fprintf(w, "  5th-95th pct:     $%.2f - $%.2f\n", d.P5, d.P95)
+	fmt.Fprintf(w, "  Best trial:       $%.2f\n", d.Best)
+	fmt.Fprintf(w, "  Chance of profit: %.2f%%\n", d.ProfitChance*100)
+}
@@ -0,0 +1,116 @@
+package analyze
+
+import (
+	"math"
+	"testing"
+
+	"msLotto/model"
+)
+
+func TestOptimizeMaxReturn(t *testing.T) {
+	games := []model.Game{
+		perDollarGame("Five", 5, 0.70),
+		perDollarGame("Three", 3, 0.69),
+		perDollarGame("One", 1, 0.10),
+	}
+	dead := perDollarGame("Dead", 2, 0.90)
+	dead.PrizeTiers[0].RemainingCount, dead.TotalRemainingPrizes = 0, 0
+
+	tests := []struct {
+		name       string
+		games      []model.Game
+		budget     int
+		want       map[string]int // tickets by game name
+		wantReturn float64
+	}{
+		// Greedy spending buys the $5 game first and has $1 left: 3.60.
+		{"beats greedy", games, 6, map[string]int{"Three": 2}, 4.14},
+		{"single best", games, 5, map[string]int{"Five": 1}, 3.50},
+		{"fills the rest", games, 9, map[string]int{"Three": 3}, 6.21},
+		{"mixes prices", games, 8, map[string]int{"Five": 1, "Three": 1}, 5.57},
+		{"only the cheapest fits", games, 2, map[string]int{"One": 2}, 0.20},
+		{"nothing fits", games, 0, map[string]int{}, 0},
+		{"skips dead games", append([]model.Game{dead}, games...), 6, map[string]int{"Three": 2}, 4.14},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, _, err := Optimize(tt.games, OptimizeOptions{Budget: tt.budget, Objective: MaxReturn, Trials: 10})
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string]int{}
+			var spent int
+			var expected float64
+			for _, it := range plan {
+				got[it.Game.Name] = it.Quantity
+				spent += it.Quantity * it.Game.Price
+				expected += float64(it.Quantity*it.Game.Price) * it.PerDollar
+			}
+			if len(got) != len(tt.want) {
+				t.Errorf("plan = %v, want %v", got, tt.want)
+			}
+			for name, n := range tt.want {
+				if got[name] != n {
+					t.Errorf("plan = %v, want %v", got, tt.want)
+					break
+				}
+			}
+			if spent > tt.budget {
+				t.Errorf("spent $%d of a $%d budget", spent, tt.budget)
+			}
+			if math.Abs(expected-tt.wantReturn) > 1e-9 {
+				t.Errorf("expected winnings = %.4f, want %.4f", expected, tt.wantReturn)
+			}
+		})
+	}
+}
+
+func TestOptimizeIncludeDead(t *testing.T) {
+	dead := perDollarGame("Dead", 2, 0.90)
+	dead.PrizeTiers[0].RemainingCount, dead.TotalRemainingPrizes = 0, 0
+	games := []model.Game{dead, perDollarGame("One", 1, 0.10)}
+
+	plan, _, err := Optimize(games, OptimizeOptions{Budget: 2, Objective: MaxReturn, Trials: 10, IncludeDead: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A dead game is a candidate, but returns nothing, so the live game
+	// still wins.
+	if len(plan) != 1 || plan[0].Game.Name != "One" || plan[0].Quantity != 2 {
+		t.Errorf("plan = %+v, want 2x One", plan)
+	}
+}
+
+func TestOptimizeUnknownObjective(t *testing.T) {
+	if _, _, err := Optimize(nil, OptimizeOptions{Budget: 5, Objective: "fun"}); err == nil {
+		t.Error("Optimize accepted an unknown objective")
+	}
+}
+
+func TestBestReturn(t *testing.T) {
+	picks := []PlanItem{
+		{Game: model.Game{Price: 5}, PerDollar: 0.70},
+		{Game: model.Game{Price: 3}, PerDollar: 0.69},
+		{Game: model.Game{Price: 1}, PerDollar: 0.10},
+	}
+	tests := []struct {
+		budget int
+		want   []int
+	}{
+		{0, []int{0, 0, 0}},
+		{1, []int{0, 0, 1}},
+		{4, []int{0, 1, 1}},
+		{6, []int{0, 2, 0}},
+		{10, []int{2, 0, 0}},
+		{11, []int{1, 2, 0}},
+	}
+	for _, tt := range tests {
+		got := bestReturn(picks, tt.budget)
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("bestReturn(budget %d) = %v, want %v", tt.budget, got, tt.want)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+
+	"msLotto/model"
+)
+
+// PackResult is the outcome of buying whole packs of one game.
+type PackResult struct {
+	Size      int
+	Winnings  Distribution
+	Winners   Distribution // winning tickets per pack
+	NoWinners float64      // share of packs without a single winner
+}
+
+// SimulatePack buys a pack of size tickets per trial. Unlike SimulateAll the
+// tickets are drawn without replacement, so a pack that has already turned
+// up a top prize is less likely to hold another, as with a real pack.
+func SimulatePack(g model.Game, size, trials int, r *rand.Rand) PackResult {
+	type tier struct {
+		value float64
+		count int
+	}
+	var base []tier
+	remaining := g.RemainingTickets()
+	winners := 0
+	for _, t := range g.PrizeTiers {
+		if v := g.TierValue(t); t.RemainingCount > 0 && v > 0 {
+			base = append(base, tier{v, t.RemainingCount})
+			winners += t.RemainingCount
+		}
+	}
+	losers := max(remaining-winners, 0)
+
+	res := PackResult{Size: size}
+	if remaining == 0 {
+		return res
+	}
+	totals := make([]float64, trials)
+	counts := make([]float64, trials)
+	tiers := make([]tier, len(base))
+	var empty int
+	for t := range totals {
+		copy(tiers, base)
+		left, lose := winners+losers, losers
+		var won float64
+		var hits int
+		for range min(size, winners+losers) {
+			n := r.IntN(left)
+			left--
+			if n < lose {
+				lose--
+				continue
+			}
+			n -= lose
+			for i := range tiers {
+				if n < tiers[i].count {
+					tiers[i].count--
+					won += tiers[i].value
+					hits++
+					break
+				}
+				n -= tiers[i].count
+			}
+		}
+		totals[t] = won
+		counts[t] = float64(hits)
+		if hits == 0 {
+			empty++
+		}
+	}
+	res.Winnings = summarize(totals, size*g.Price)
+	res.Winners = summarize(counts, 0)
+	res.NoWinners = float64(empty) / float64(trials)
+	return res
+}
+
+// WritePackReport compares what the overall odds promise a pack holds with
+// what the remaining prizes and the simulation say it does.
+func WritePackReport(w io.Writer, g model.Game, res PackResult) {
+	cost := res.Size * g.Price
+	_, _, hit := TicketStats(g)
+	fmt.Fprintf(w, "Buying a pack of %d %s ($%d) tickets for $%d\n", res.Size, g.Name, g.Price, cost)
+	if g.Odds > 0 {
+		fmt.Fprintf(w, "  Winners by overall odds 1:%.2f:  %.1f\n", g.Odds, float64(res.Size)/g.Odds)
+	}
+	fmt.Fprintf(w, "  Winners from remaining prizes:  %.1f\n", float64(res.Size)*hit)
+	fmt.Fprintf(w, "  Simulated winners:              %.1f mean, %.0f-%.0f (5th-95th pct)\n", res.Winners.Mean, res.Winners.P5, res.Winners.P95)
+	fmt.Fprintf(w, "  Packs without a winner:         %.2f%%\n", res.NoWinners*100)
+	fmt.Fprintf(w, "  Expected return (EV):           $%.2f (net %.2f)\n", float64(res.Size)*(float64(g.Price)-g.EV()), -float64(res.Size)*g.EV())
+	fmt.Fprintf(w, "  Simulated mean:                 $%.2f over %d trials\n", res.Winnings.Mean, res.Winnings.Trials)
+	fmt.Fprintf(w, "  Median:                         $%.2f\n", res.Winnings.Median)
+	fmt.Fprintf(w, "  5th-95th pct:                   $%.2f - $%.2f\n", res.Winnings.P5, res.Winnings.P95)
+	fmt.Fprintf(w, "  Chance of profit:               %.2f%%\n", res.Winnings.ProfitChance*100)
+}
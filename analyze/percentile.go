@@ -0,0 +1,57 @@
+package analyze
+
+import (
+	"sort"
+
+	"msLotto/journal"
+	"msLotto/model"
+)
+
+// ReturnPerDollar is how much of each dollar spent a ticket hands back: 1
+// plus its model.ReturnRate.
+func ReturnPerDollar(price int, ev float64) float64 {
+	if price == 0 {
+		return 0
+	}
+	return 1 + model.ReturnRate(price, ev)
+}
+
+// PricePeers holds the return per dollar of every game observed at each
+// price, sorted: the current run, plus journaled snapshots when -peer-history
+// is set.
+type PricePeers map[int][]float64
+
+// NewPricePeers collects the peers of the current games and any history.
+func NewPricePeers(games []model.Game, history []journal.Snapshot) PricePeers {
+	peers := PricePeers{}
+	for _, g := range games {
+		peers[g.Price] = append(peers[g.Price], ReturnPerDollar(g.Price, g.EV()))
+	}
+	for _, s := range history {
+		for _, e := range s.Index {
+			peers[e.Price] = append(peers[e.Price], ReturnPerDollar(e.Price, e.EV))
+		}
+	}
+	for _, rs := range peers {
+		sort.Float64s(rs)
+	}
+	return peers
+}
+
+// Percentile is the share of same-price peers whose return per dollar g
+// beats, 0-100. A game with no peers at its price scores 100. A game that
+// isn't among the peers itself, such as an ended game, ranks against all
+// of them.
+func (p PricePeers) Percentile(g model.Game) float64 {
+	peers := p[g.Price]
+	r := ReturnPerDollar(g.Price, g.EV())
+	below := sort.SearchFloat64s(peers, r)
+	others := len(peers)
+	if below < len(peers) && peers[below] == r {
+		others--
+	}
+	if others == 0 {
+		return 100
+	}
+	return float64(below) / float64(others) * 100
+}
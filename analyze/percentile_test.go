@@ -0,0 +1,34 @@
+package analyze
+
+import (
+	"math"
+	"testing"
+
+	"msLotto/model"
+)
+
+func TestPricePercentile(t *testing.T) {
+	low := perDollarGame("Low", 5, 0.6)
+	mid := perDollarGame("Mid", 5, 0.7)
+	high := perDollarGame("High", 5, 0.8)
+	peers := NewPricePeers([]model.Game{low, mid, high}, nil)
+
+	tests := []struct {
+		name string
+		g    model.Game
+		want float64
+	}{
+		{"worst", low, 0},
+		{"middle", mid, 50},
+		{"best", high, 100},
+		// Not among its peers, so it beats all three rather than 3 of 2.
+		{"ended, better than all", perDollarGame("Ended", 5, 0.9), 100},
+		{"ended, in between", perDollarGame("Ended", 5, 0.65), 100.0 / 3},
+		{"no peers at its price", perDollarGame("Alone", 10, 0.7), 100},
+	}
+	for _, tt := range tests {
+		if got := peers.Percentile(tt.g); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("%s: Percentile = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
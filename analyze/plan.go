@@ -0,0 +1,144 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"msLotto/model"
+)
+
+// TicketStats describes a single ticket's winnings: the mean, standard
+// deviation, and chance of winning anything at all.
+func TicketStats(g model.Game) (mean, sd, hit float64) {
+	remaining := g.RemainingTickets()
+	if remaining == 0 {
+		return 0, 0, 0
+	}
+	var sq float64
+	for _, p := range g.PrizeTiers {
+		v := g.TierValue(p)
+		if p.RemainingCount <= 0 || v <= 0 {
+			continue
+		}
+		prob := float64(p.RemainingCount) / float64(remaining)
+		mean += prob * v
+		sq += prob * v * v
+		hit += prob
+	}
+	return mean, math.Sqrt(max(sq-mean*mean, 0)), hit
+}
+
+// PlanOptions are the shopper's constraints for a store visit.
+type PlanOptions struct {
+	Budget       int
+	MinHitRate   float64 // minimum chance a ticket wins something, 0-1
+	MaxSDPerDoll float64 // maximum standard deviation of winnings per $1 of price, 0 for no limit
+	IncludeDead  bool    // consider games with no prizes left too
+}
+
+// PlanItem is one line of the shopping list.
+type PlanItem struct {
+	Game      model.Game
+	Quantity  int
+	PerDollar float64 // expected return per $1
+	HitRate   float64
+}
+
+// PlanVisit picks the best qualifying game at each price point and spends
+// the budget across them, giving each extra ticket to the pick whose
+// return per dollar, divided by the tickets it already has, is highest. That
+// favors the best games while still covering several price points.
+func PlanVisit(games []model.Game, opts PlanOptions) []PlanItem {
+	items := planCandidates(games, opts)
+	left := opts.Budget
+	for {
+		pick := -1
+		var pickScore float64
+		for i, it := range items {
+			if it.Game.Price > left {
+				continue
+			}
+			score := it.PerDollar / float64(it.Quantity+1)
+			if pick < 0 || score > pickScore {
+				pick, pickScore = i, score
+			}
+		}
+		if pick < 0 {
+			break
+		}
+		items[pick].Quantity++
+		left -= items[pick].Game.Price
+	}
+
+	plan := items[:0]
+	for _, it := range items {
+		if it.Quantity > 0 {
+			plan = append(plan, it)
+		}
+	}
+	return plan
+}
+
+// planCandidates is the best game by return per dollar at each price point
+// within the budget that meets opts, best first.
+func planCandidates(games []model.Game, opts PlanOptions) []PlanItem {
+	best := map[int]PlanItem{}
+	for _, g := range games {
+		if g.Price <= 0 || g.Price > opts.Budget || (g.Dead() && !opts.IncludeDead) {
+			continue
+		}
+		mean, sd, hit := TicketStats(g)
+		if hit < opts.MinHitRate {
+			continue
+		}
+		if opts.MaxSDPerDoll > 0 && sd/float64(g.Price) > opts.MaxSDPerDoll {
+			continue
+		}
+		item := PlanItem{Game: g, PerDollar: mean / float64(g.Price), HitRate: hit}
+		if cur, ok := best[g.Price]; !ok || item.PerDollar > cur.PerDollar {
+			best[g.Price] = item
+		}
+	}
+
+	items := make([]PlanItem, 0, len(best))
+	for _, it := range best {
+		items = append(items, it)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].PerDollar != items[j].PerDollar {
+			return items[i].PerDollar > items[j].PerDollar
+		}
+		return items[i].Game.Price < items[j].Game.Price
+	})
+	return items
+}
+
+// FormatPlan renders the plan as a shopping list, usable on screen or as a
+// notification body.
+func FormatPlan(plan []PlanItem, budget int) string {
+	var b strings.Builder
+	var spent int
+	var expected float64
+	for i, it := range plan {
+		cost := it.Quantity * it.Game.Price
+		spent += cost
+		expected += float64(cost) * it.PerDollar
+		fmt.Fprintf(&b, "%d. %dx %s ($%d) - $%d, returns $%.2f per $1, %.0f%% win something\n",
+			i+1, it.Quantity, it.Game.Name, it.Game.Price, cost, it.PerDollar, it.HitRate*100)
+	}
+	fmt.Fprintf(&b, "Total: $%d of $%d budget, expected back $%.2f", spent, budget, expected)
+	return b.String()
+}
+
+// WritePlan prints the plan with a heading.
+func WritePlan(w io.Writer, plan []PlanItem, budget int) {
+	fmt.Fprintf(w, "Store visit plan for $%d:\n", budget)
+	if len(plan) == 0 {
+		fmt.Fprintln(w, "No games meet the constraints.")
+		return
+	}
+	fmt.Fprintln(w, FormatPlan(plan, budget))
+}
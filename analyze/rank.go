@@ -0,0 +1,145 @@
+// Package analyze ranks, simulates and plans purchases over scraped games.
+package analyze
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strings"
+
+	"msLotto/model"
+)
+
+// SortByEV orders games by EV compared to the cent, as printed, with game
+// number and then URL breaking ties so the same data always produces the
+// same order. The CSV has always listed the highest EV first. Dead games go
+// last either way.
+func SortByEV(games []model.Game, highestFirst bool) {
+	sort.SliceStable(games, func(i, j int) bool {
+		if c := compareDead(&games[i], &games[j]); c != 0 {
+			return c < 0
+		}
+		a, b := model.Round(games[i].EV(), 2), model.Round(games[j].EV(), 2)
+		if a != b {
+			return (a > b) == highestFirst
+		}
+		if games[i].GameNumber != games[j].GameNumber {
+			return games[i].GameNumber < games[j].GameNumber
+		}
+		return games[i].URL < games[j].URL
+	})
+}
+
+// sortKeys are the orders SortBy accepts. Each compares two games and returns
+// 0 to leave the order to the tiebreakers.
+var sortKeys = map[string]func(a, b *model.Game) int{
+	// ev is the CSV's classic order, largest expected loss first.
+	"ev":         func(a, b *model.Game) int { return cmp.Compare(model.Round(b.EV(), 2), model.Round(a.EV(), 2)) },
+	"best":       func(a, b *model.Game) int { return cmp.Compare(model.Round(a.EV(), 2), model.Round(b.EV(), 2)) },
+	"price":      func(a, b *model.Game) int { return cmp.Compare(a.Price, b.Price) },
+	"name":       func(a, b *model.Game) int { return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) },
+	"payout":     func(a, b *model.Game) int { return cmp.Compare(b.PayoutRemaining(), a.PayoutRemaining()) },
+	"percentile": nil, // needs the peers, see SortBy
+	// return_per_ticket puts the best game per dollar first, whatever its
+	// price.
+	"return_per_ticket": func(a, b *model.Game) int {
+		return cmp.Compare(model.Round(b.ReturnRate(), 4), model.Round(a.ReturnRate(), 4))
+	},
+}
+
+// SortKeys lists the names SortBy accepts.
+func SortKeys() []string {
+	keys := make([]string, 0, len(sortKeys))
+	for k := range sortKeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SortBy orders games by the named key with the same game number and URL
+// tiebreakers as SortByEV, and dead games last. The percentile key ranks
+// games against peers, or against each other when peers is nil.
+func SortBy(games []model.Game, key string, peers PricePeers) error {
+	compare, ok := sortKeys[key]
+	if !ok {
+		return fmt.Errorf("unknown sort %q (have %s)", key, strings.Join(SortKeys(), ", "))
+	}
+	if key == "percentile" {
+		if peers == nil {
+			peers = NewPricePeers(games, nil)
+		}
+		compare = func(a, b *model.Game) int { return cmp.Compare(peers.Percentile(*b), peers.Percentile(*a)) }
+	}
+	sort.SliceStable(games, func(i, j int) bool {
+		if c := compareDead(&games[i], &games[j]); c != 0 {
+			return c < 0
+		}
+		if c := compare(&games[i], &games[j]); c != 0 {
+			return c < 0
+		}
+		if games[i].GameNumber != games[j].GameNumber {
+			return games[i].GameNumber < games[j].GameNumber
+		}
+		return games[i].URL < games[j].URL
+	})
+	return nil
+}
+
+// GroupByPrice splits games into one group per ticket price, cheapest first,
+// with the smallest expected loss leading each group.
+func GroupByPrice(games []model.Game) [][]model.Game {
+	byPrice := map[int][]model.Game{}
+	var prices []int
+	for _, g := range games {
+		if _, ok := byPrice[g.Price]; !ok {
+			prices = append(prices, g.Price)
+		}
+		byPrice[g.Price] = append(byPrice[g.Price], g)
+	}
+	sort.Ints(prices)
+
+	groups := make([][]model.Game, 0, len(prices))
+	for _, p := range prices {
+		group := byPrice[p]
+		SortByEV(group, false)
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// compareDead puts live games before dead ones.
+func compareDead(a, b *model.Game) int {
+	switch {
+	case a.Dead() == b.Dead():
+		return 0
+	case b.Dead():
+		return -1
+	}
+	return 1
+}
+
+// Listed leaves out the games that had left the active list, which a run
+// loaded from -db history carries with their final prize counts.
+func Listed(games []model.Game) []model.Game {
+	var listed []model.Game
+	for _, g := range games {
+		if g.Ended.IsZero() {
+			listed = append(listed, g)
+		}
+	}
+	return listed
+}
+
+// SplitDead separates the games with prizes left from the dead ones,
+// keeping their order.
+func SplitDead(games []model.Game) (live, dead []model.Game) {
+	for _, g := range games {
+		if g.Dead() {
+			dead = append(dead, g)
+		} else {
+			live = append(live, g)
+		}
+	}
+	return live, dead
+}
@@ -0,0 +1,146 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"sort"
+
+	"msLotto/model"
+)
+
+// ticketSampler draws the winnings of one ticket from a game's remaining
+// prize distribution. Tickets are drawn with replacement, which is accurate
+// as long as the purchase is tiny next to the remaining print run.
+type ticketSampler struct {
+	cum    []float64 // cumulative win probability per tier
+	values []float64
+}
+
+func newTicketSampler(g model.Game) ticketSampler {
+	var s ticketSampler
+	remaining := g.RemainingTickets()
+	if remaining == 0 {
+		return s
+	}
+	var p float64
+	for _, t := range g.PrizeTiers {
+		v := g.TierValue(t)
+		if t.RemainingCount <= 0 || v <= 0 {
+			continue
+		}
+		p += float64(t.RemainingCount) / float64(remaining)
+		s.cum = append(s.cum, p)
+		s.values = append(s.values, v)
+	}
+	return s
+}
+
+func (s ticketSampler) draw(r *rand.Rand) float64 {
+	u := r.Float64()
+	i := sort.SearchFloat64s(s.cum, u)
+	if i < len(s.cum) && u == s.cum[i] {
+		i++
+	}
+	if i >= len(s.values) {
+		return 0
+	}
+	return s.values[i]
+}
+
+// Distribution summarizes the total winnings across simulated trials.
+type Distribution struct {
+	Trials       int
+	Cost         int
+	Mean         float64
+	Median       float64
+	P5, P25      float64
+	P75, P95     float64
+	Best         float64
+	ProfitChance float64 // share of trials that won back more than Cost
+}
+
+func summarize(totals []float64, cost int) Distribution {
+	d := Distribution{Trials: len(totals), Cost: cost}
+	if len(totals) == 0 {
+		return d
+	}
+	sort.Float64s(totals)
+	var sum float64
+	var profit int
+	for _, t := range totals {
+		sum += t
+		if t > float64(cost) {
+			profit++
+		}
+	}
+	pct := func(p float64) float64 {
+		return totals[int(p*float64(len(totals)-1))]
+	}
+	d.Mean = sum / float64(len(totals))
+	d.Median = pct(0.5)
+	d.P5, d.P25, d.P75, d.P95 = pct(0.05), pct(0.25), pct(0.75), pct(0.95)
+	d.Best = totals[len(totals)-1]
+	d.ProfitChance = float64(profit) / float64(len(totals))
+	return d
+}
+
+// SimulateAll buys perGame tickets of every game in each trial and returns the
+// distribution of combined winnings.
+func SimulateAll(games []model.Game, perGame, trials int, r *rand.Rand) Distribution {
+	samplers := make([]ticketSampler, len(games))
+	cost := 0
+	for i, g := range games {
+		samplers[i] = newTicketSampler(g)
+		cost += g.Price * perGame
+	}
+
+	totals := make([]float64, trials)
+	for t := range totals {
+		var won float64
+		for _, s := range samplers {
+			for range perGame {
+				won += s.draw(r)
+			}
+		}
+		totals[t] = won
+	}
+	return summarize(totals, cost)
+}
+
+// Simulate buys tickets tickets of one game in each trial and returns the
+// distribution of their winnings.
+func Simulate(g model.Game, tickets, trials int, r *rand.Rand) Distribution {
+	return SimulateAll([]model.Game{g}, tickets, trials, r)
+}
+
+// WriteSimulateReport prints how the winnings from tickets tickets of g
+// spread across the simulated trials.
+func WriteSimulateReport(w io.Writer, g model.Game, tickets int, d Distribution) {
+	fmt.Fprintf(w, "Buying %d %s ($%d) ticket(s)\n", tickets, g.Name, g.Price)
+	writeDistribution(w, float64(tickets)*(float64(g.Price)-g.EV()), d)
+}
+
+// WriteSimulateAllReport prints the "one of each" report: what the basket costs,
+// what it should return on average, and how the simulated outcomes spread.
+func WriteSimulateAllReport(w io.Writer, games []model.Game, perGame int, d Distribution) {
+	var expected float64
+	for _, g := range games {
+		expected += float64(perGame) * (float64(g.Price) - g.EV())
+	}
+	fmt.Fprintf(w, "Buying %d ticket(s) of each of %d games\n", perGame, len(games))
+	writeDistribution(w, expected, d)
+}
+
+// writeDistribution prints the body shared by the simulate reports, below
+// their "Buying ..." line.
+func writeDistribution(w io.Writer, expected float64, d Distribution) {
+	fmt.Fprintf(w, "  Cost:             $%d\n", d.Cost)
+	fmt.Fprintf(w, "  Expected return:  $%.2f (net %.2f)\n", expected, expected-float64(d.Cost))
+	fmt.Fprintf(w, "  Simulated mean:   $%.2f over %d trials\n", d.Mean, d.Trials)
+	fmt.Fprintf(w, "  Median:           $%.2f\n", d.Median)
+	fmt.Fprintf(w, "  5th-95th pct:     $%.2f - $%.2f\n", d.P5, d.P95)
+	fmt.Fprintf(w, "  25th-75th pct:    $%.2f - $%.2f\n", d.P25, d.P75)
+	fmt.Fprintf(w, "  Best trial:       $%.2f\n", d.Best)
+	fmt.Fprintf(w, "  Chance of profit: %.2f%%\n", d.ProfitChance*100)
+}
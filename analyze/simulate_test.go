@@ -0,0 +1,83 @@
+package analyze
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"msLotto/model"
+)
+
+// coinFlip is a $1 game where half the tickets win prize dollars.
+func coinFlip(prize int) model.Game {
+	return model.Game{
+		Name:                 "Coin Flip",
+		Price:                1,
+		Odds:                 2,
+		PrizeTiers:           []model.PrizeTier{{Value: prize, OriginalCount: 1000, RemainingCount: 1000}},
+		TotalOriginalPrizes:  1000,
+		TotalRemainingPrizes: 1000,
+	}
+}
+
+func TestSimulateCertainPrize(t *testing.T) {
+	// Every ticket wins $3, so every trial wins the same.
+	g := model.Game{
+		Price:                5,
+		Odds:                 1,
+		PrizeTiers:           []model.PrizeTier{{Value: 3, OriginalCount: 100, RemainingCount: 100}},
+		TotalOriginalPrizes:  100,
+		TotalRemainingPrizes: 100,
+	}
+	d := Simulate(g, 4, 100, rand.New(rand.NewPCG(1, 0)))
+	want := Distribution{Trials: 100, Cost: 20, Mean: 12, Median: 12, P5: 12, P25: 12, P75: 12, P95: 12, Best: 12}
+	if d != want {
+		t.Errorf("Simulate = %+v, want %+v", d, want)
+	}
+}
+
+func TestSimulateConverges(t *testing.T) {
+	d := Simulate(coinFlip(3), 1, 20000, rand.New(rand.NewPCG(1, 0)))
+	if math.Abs(d.Mean-1.5) > 0.05 {
+		t.Errorf("mean = %.3f, want about 1.5", d.Mean)
+	}
+	if math.Abs(d.ProfitChance-0.5) > 0.02 {
+		t.Errorf("chance of profit = %.3f, want about 0.5", d.ProfitChance)
+	}
+	if d.P5 != 0 || d.P95 != 3 {
+		t.Errorf("5th-95th percentile = %v-%v, want 0-3", d.P5, d.P95)
+	}
+
+	again := Simulate(coinFlip(3), 1, 20000, rand.New(rand.NewPCG(1, 0)))
+	if again != d {
+		t.Errorf("the same seed simulated %+v, then %+v", d, again)
+	}
+}
+
+func TestSimulateAll(t *testing.T) {
+	five := coinFlip(7)
+	five.Name, five.Price = "Five", 5
+	d := SimulateAll([]model.Game{coinFlip(3), five}, 2, 20000, rand.New(rand.NewPCG(1, 0)))
+	if d.Cost != 12 {
+		t.Errorf("cost = %d, want 12", d.Cost)
+	}
+	// 2 * 1.5 + 2 * 3.5
+	if math.Abs(d.Mean-10) > 0.5 {
+		t.Errorf("mean = %.3f, want about 10", d.Mean)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	totals := make([]float64, 101)
+	for i := range totals {
+		totals[i] = float64(100 - i)
+	}
+	d := summarize(totals, 89)
+	want := Distribution{Trials: 101, Cost: 89, Mean: 50, Median: 50, P5: 5, P25: 25, P75: 75, P95: 95, Best: 100, ProfitChance: 11.0 / 101}
+	if d != want {
+		t.Errorf("summarize = %+v, want %+v", d, want)
+	}
+	if d := summarize(nil, 5); d != (Distribution{Cost: 5}) {
+		t.Errorf("summarize of no trials = %+v", d)
+	}
+}
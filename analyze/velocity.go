@@ -0,0 +1,89 @@
+package analyze
+
+import (
+	"time"
+
+	"msLotto/model"
+)
+
+// ClaimPoint is a game's total remaining prizes as one earlier run saw it.
+type ClaimPoint struct {
+	GameNumber int
+	URL        string
+	At         time.Time
+	Remaining  int
+}
+
+// minVelocitySpan is the shortest history a claim rate is worked out over;
+// two runs minutes apart say nothing about daily sales.
+const minVelocitySpan = 12 * time.Hour
+
+// ClaimHistory holds each game's earlier observations, oldest first, when
+// the current games were scraped, and how far back claim rates look.
+type ClaimHistory struct {
+	points map[string][]ClaimPoint
+	now    time.Time
+	window time.Duration
+}
+
+// NewClaimHistory collects the observations ClaimsPerDay measures today's
+// games against, e.g. from the -db history, with now the current run's
+// start. Only observations within window of now count, which smooths the
+// rate over recent sales rather than the game's whole life; 0 uses them all.
+func NewClaimHistory(points []ClaimPoint, now time.Time, window time.Duration) *ClaimHistory {
+	h := &ClaimHistory{points: map[string][]ClaimPoint{}, now: now, window: window}
+	for _, p := range points {
+		k := GameKey(model.Game{GameNumber: p.GameNumber, URL: p.URL})
+		h.points[k] = append(h.points[k], p)
+	}
+	return h
+}
+
+// ClaimsPerDay is how many of g's prizes have been claimed per day since the
+// oldest observation in the window. ok is false when there is too little
+// history to tell, including when c is nil.
+func (c *ClaimHistory) ClaimsPerDay(g model.Game) (rate float64, ok bool) {
+	if c == nil {
+		return 0, false
+	}
+	h := c.points[GameKey(g)]
+	for c.window > 0 && len(h) > 0 && c.now.Sub(h[0].At) > c.window {
+		h = h[1:]
+	}
+	if len(h) == 0 {
+		return 0, false
+	}
+	first := h[0]
+	span := c.now.Sub(first.At)
+	if span < minVelocitySpan {
+		return 0, false
+	}
+	claimed := first.Remaining - g.TotalRemainingPrizes
+	return max(float64(claimed), 0) / span.Hours() * 24, true
+}
+
+// maxSellOutDays caps projections; a game that slow is effectively not
+// selling, and the date would overflow a time.Duration soon after.
+const maxSellOutDays = 10 * 365
+
+// SellOutDate projects when g's estimated remaining tickets will be gone at
+// the current claim rate, assuming losing tickets sell at the same pace as
+// winners. ok is false without a rate, or when the game would take more
+// than maxSellOutDays.
+func (c *ClaimHistory) SellOutDate(g model.Game) (date time.Time, ok bool) {
+	rate, ok := c.ClaimsPerDay(g)
+	if !ok || rate <= 0 || g.TotalRemainingPrizes <= 0 {
+		return time.Time{}, false
+	}
+	// Tickets sold per day, from prizes claimed per day and the share of
+	// the remaining tickets that still hold a prize.
+	perDay := rate * float64(g.RemainingTickets()) / float64(g.TotalRemainingPrizes)
+	if perDay <= 0 {
+		return time.Time{}, false
+	}
+	days := float64(g.RemainingTickets()) / perDay
+	if days > maxSellOutDays {
+		return time.Time{}, false
+	}
+	return c.now.Add(time.Duration(days * 24 * float64(time.Hour))), true
+}
@@ -0,0 +1,78 @@
+package analyze
+
+import (
+	"fmt"
+	"sort"
+
+	"msLotto/model"
+)
+
+// BestReturn finds the live game returning the most per dollar.
+func BestReturn(games []model.Game) (model.Game, float64, bool) {
+	live, _ := SplitDead(games)
+	var best model.Game
+	bestReturn, found := 0.0, false
+	for _, g := range live {
+		if g.Price <= 0 {
+			continue
+		}
+		if r := ReturnPerDollar(g.Price, g.EV()); !found || r > bestReturn {
+			best, bestReturn, found = g, r, true
+		}
+	}
+	return best, bestReturn, found
+}
+
+// Verdict judges today's best game against the best game of every recorded
+// run.
+type Verdict struct {
+	Best       model.Game
+	Return     float64 // Best's return per $1
+	Runs       int     // recorded runs it is compared with
+	Percentile float64 // share of those runs whose best returned less, ties counting half, 0-100
+	Median     float64 // median of the runs' best returns
+	Top        float64 // highest of the runs' best returns
+}
+
+// Percentiles at or above goodDay, and at or below poorDay, make a verdict
+// good or poor; anything between is an ordinary day.
+const (
+	goodDay = 75
+	poorDay = 25
+)
+
+// JudgeDay compares the best game among games with history, the best
+// return per dollar of each earlier run. ok is false when no game is live.
+func JudgeDay(games []model.Game, history []float64) (v Verdict, ok bool) {
+	v.Best, v.Return, ok = BestReturn(games)
+	if !ok || len(history) == 0 {
+		return v, ok
+	}
+	sorted := append([]float64(nil), history...)
+	sort.Float64s(sorted)
+	// Ties count half, so a day like every other lands mid-range.
+	below := sort.SearchFloat64s(sorted, v.Return)
+	tied := sort.Search(len(sorted), func(i int) bool { return sorted[i] > v.Return }) - below
+	v.Runs = len(sorted)
+	v.Percentile = 100 * (float64(below) + float64(tied)/2) / float64(len(sorted))
+	v.Median = sorted[len(sorted)/2]
+	v.Top = sorted[len(sorted)-1]
+	return v, ok
+}
+
+// Summary is the verdict in one line.
+func (v Verdict) Summary() string {
+	best := fmt.Sprintf("the best game, %s ($%d), returns $%.4f per $1", v.Best.Name, v.Best.Price, v.Return)
+	if v.Runs == 0 {
+		return "No history to judge by yet: " + best + "."
+	}
+	verdict := "An ordinary day to buy"
+	switch {
+	case v.Percentile >= goodDay:
+		verdict = "A good day to buy"
+	case v.Percentile <= poorDay:
+		verdict = "A poor day to buy"
+	}
+	return fmt.Sprintf("%s: %s, better than %.0f%% of %d recorded runs (median $%.4f, best $%.4f).",
+		verdict, best, v.Percentile, v.Runs, v.Median, v.Top)
+}
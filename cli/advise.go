@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"msLotto/analyze"
+)
+
+func runAdvise(args []string) error {
+	fs := flag.NewFlagSet("advise", flag.ContinueOnError)
+	bankroll := fs.Float64("bankroll", 500, "dollars set aside for tickets")
+	top := fs.Int("top", 10, "games listed, best return per dollar first (0 for all)")
+	settingsOf := settingsFlags(fs)
+	asOf, dbPath := asOfFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *bankroll <= 0 {
+		return errors.New("-bankroll must be positive")
+	}
+	if *top < 0 {
+		return errors.New("-top must not be negative")
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	games, err := analysisGames(*asOf, *dbPath, settings)
+	if err != nil {
+		return err
+	}
+	return analyze.WriteAdvice(os.Stdout, analyze.Advise(games, *bankroll, *top), *bankroll)
+}
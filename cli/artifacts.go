@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"archive/zip"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"msLotto/export"
+	"msLotto/notify"
+	"msLotto/pipeline"
+	"msLotto/scrape"
+)
+
+// scrubArgs returns the command line with credentials, and with -redact
+// paths and internal URLs, replaced.
+func scrubArgs(args []string, red *export.Redactor) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = red.String(a)
+	}
+	return out
+}
+
+// keepArtifacts starts recording a run into dir: logger is teed into run.log
+// here, and the returned stage saves raw pages, parsed games and the
+// scrubbed command line once parsing is done.
+func keepArtifacts(dir string, red *export.Redactor, logger *log.Logger) (pipeline.Stage, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return pipeline.Stage{}, err
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "raw"), 0o755); err != nil {
+		return pipeline.Stage{}, err
+	}
+	logFile, err := os.Create(filepath.Join(dir, "run.log"))
+	if err != nil {
+		return pipeline.Stage{}, err
+	}
+	logger.SetOutput(io.MultiWriter(os.Stderr, logFile))
+
+	return pipeline.Stage{Name: "artifacts", Run: func(r *pipeline.Run) error {
+		for _, p := range r.Pages {
+			name := strings.ReplaceAll(scrape.ExtractGameName(p.URL), " ", "-") + ".html"
+			if err := os.WriteFile(filepath.Join(dir, "raw", name), p.Body, 0o644); err != nil {
+				return err
+			}
+		}
+		if err := export.WriteJSONFile(filepath.Join(dir, "parsed.json"), red.Games(r.Games)); err != nil {
+			return err
+		}
+		info := fmt.Sprintf("run: %s\nstarted: %s\nargs: %s\n", r.ID, r.Started.Format(time.RFC3339), strings.Join(scrubArgs(os.Args, red), " "))
+		return os.WriteFile(filepath.Join(dir, "run.txt"), []byte(info), 0o644)
+	}}, nil
+}
+
+// writeEvents saves the digest's events next to the other artifacts.
+func writeEvents(dir string, events []notify.Event, red *export.Redactor) error {
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "[%s] %s\n%s\n\n", e.Severity, red.String(e.Title), red.String(e.Message))
+	}
+	return os.WriteFile(filepath.Join(dir, "events.txt"), []byte(b.String()), 0o644)
+}
+
+// BundleArtifacts zips everything under dir into out.
+func BundleArtifacts(dir, out string) error {
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		w, err := zw.Create(filepath.ToSlash(rel))
+		if err != nil {
+			return err
+		}
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		_, err = io.Copy(w, src)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+func runDebugBundle(args []string) error {
+	fs := flag.NewFlagSet("debug-bundle", flag.ContinueOnError)
+	dir := fs.String("dir", "mslotto_debug", "artifacts directory written by -keep-artifacts")
+	out := fs.String("out", "mslotto-debug.zip", "zip file to write")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(*dir); err != nil {
+		return fmt.Errorf("no artifacts in %s; run a scrape with -keep-artifacts %s first", *dir, *dir)
+	}
+	if err := BundleArtifacts(*dir, *out); err != nil {
+		return err
+	}
+	fmt.Println("Debug bundle written to", *out)
+	return nil
+}
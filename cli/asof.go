@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/model"
+	"msLotto/notify"
+	"msLotto/pipeline"
+	"msLotto/store"
+)
+
+// asOfLayouts are the timestamps -as-of accepts, in local time unless the
+// zone is given.
+var asOfLayouts = []string{time.RFC3339, "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"}
+
+// parseAsOf reads an -as-of timestamp. A bare date means the end of that
+// day, so the day's last run counts.
+func parseAsOf(s string) (time.Time, error) {
+	for _, layout := range asOfLayouts {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			continue
+		}
+		if layout == "2006-01-02" {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("-as-of %q: want a date like 2025-03-01 or a time like 2025-03-01 18:00", s)
+}
+
+// asOfFlags adds -as-of and the -db it reads from to an analysis command.
+func asOfFlags(fs *flag.FlagSet) (asOf, dbPath *string) {
+	asOf = fs.String("as-of", "", "analyze the games as the last run recorded in -db at or before this time saw them, e.g. 2025-03-01 or \"2025-03-01 18:00\", instead of scraping")
+	dbPath = fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db, for -as-of")
+	return asOf, dbPath
+}
+
+// runAsOf finds the latest run in runs, newest first, started at or before t.
+func runAsOf(runs []time.Time, t time.Time) (int, bool) {
+	for i, started := range runs {
+		if !started.After(t) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// analysisGames scrapes the active games, or with asOf set loads the run
+// recorded in the database at dbPath as of then, noting which run it used.
+// Either way the games are worked out under settings.
+func analysisGames(asOf, dbPath string, settings *model.Settings) ([]model.Game, error) {
+	if asOf == "" {
+		var games []model.Game
+		p := pipeline.New(pipeline.Options{Concurrency: 75, Settings: settings}, func(r *pipeline.Run) error {
+			games = r.Games
+			return nil
+		})
+		if err := p.Run(&pipeline.Run{Digest: &notify.Digest{}}); err != nil {
+			return nil, err
+		}
+		return games, nil
+	}
+
+	t, err := parseAsOf(asOf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", dbPath, dbPath)
+	}
+	db, err := store.OpenSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	runs, err := db.Runs()
+	if err != nil {
+		return nil, err
+	}
+	i, ok := runAsOf(runs, t)
+	if !ok {
+		return nil, fmt.Errorf("%s has no run recorded at or before %s", dbPath, t.Format("2006-01-02 15:04"))
+	}
+	games, err := db.LoadRun(runs[i])
+	if err != nil {
+		return nil, err
+	}
+	games = analyze.Listed(games)
+	model.ApplySettings(games, settings)
+	fmt.Fprintf(os.Stderr, "Using the run of %s\n", runs[i].Local().Format("2006-01-02 15:04"))
+	return games, nil
+}
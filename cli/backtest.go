@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/model"
+	"msLotto/store"
+)
+
+func runBacktest(args []string) error {
+	fs := flag.NewFlagSet("backtest", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	settingsOf := settingsFlags(fs)
+	sortKey := fs.String("sort", "best", "ranking the recommendations are taken from: "+strings.Join(analyze.SortKeys(), ", "))
+	top := fs.Int("top", 3, "games recommended each period")
+	step := fs.Int("step", 7, "days between recommendations")
+	horizon := fs.Int("horizon", 7, "days after a recommendation it is judged")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+	if err := analyze.SortBy(nil, *sortKey, nil); err != nil {
+		return err
+	}
+	if *top < 1 || *step < 1 || *horizon < 1 {
+		return errors.New("-top, -step and -horizon must be at least 1")
+	}
+
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := db.Runs()
+	if err != nil {
+		return err
+	}
+	slices.Reverse(runs)
+
+	// A period's later run is often the next period's pick, so keep what
+	// has been read.
+	loaded := map[time.Time][]model.Game{}
+	load := func(started time.Time) ([]model.Game, error) {
+		if games, ok := loaded[started]; ok {
+			return slices.Clone(games), nil
+		}
+		games, err := db.LoadRun(started)
+		if err != nil {
+			return nil, err
+		}
+		model.ApplySettings(games, settings)
+		loaded[started] = games
+		return slices.Clone(games), nil
+	}
+
+	day := 24 * time.Hour
+	opts := analyze.BacktestOptions{Sort: *sortKey, Top: *top, Step: time.Duration(*step) * day, Horizon: time.Duration(*horizon) * day}
+	periods, err := analyze.Backtest(runs, load, opts)
+	if err != nil {
+		return err
+	}
+	return analyze.WriteBacktest(os.Stdout, periods, opts)
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"runtime"
+	"time"
+
+	"msLotto/mslottotest"
+	"msLotto/notify"
+	"msLotto/pipeline"
+)
+
+// recordedPages loads every .html file in dir, such as the raw pages saved by
+// -keep-artifacts.
+func recordedPages(dir string) ([]pipeline.Page, error) {
+	recorded, err := mslottotest.LoadPages(dir)
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]pipeline.Page, len(recorded))
+	for i, p := range recorded {
+		pages[i] = pipeline.Page{URL: "https://www.mslottery.com/games/" + p.Slug + "/", Body: p.Body}
+	}
+	return pages, nil
+}
+
+// benchResult is one pass of the parse and analyze stages.
+type benchResult struct {
+	elapsed time.Duration
+	allocs  uint64
+	bytes   uint64
+}
+
+// benchPass replays pages through the standard parse and analyze stages,
+// with discovery, fetching and writing taken out.
+func benchPass(pages []pipeline.Page, concurrency int) (benchResult, error) {
+	p := pipeline.New(pipeline.Options{Concurrency: concurrency}, func(*pipeline.Run) error { return nil })
+	replay := pipeline.Stage{Name: "replay", Run: func(r *pipeline.Run) error {
+		ch := make(chan pipeline.Page, len(pages))
+		for _, pg := range pages {
+			ch <- pg
+		}
+		close(ch)
+		r.Fetched = ch
+		return nil
+	}}
+	p.Stages = append([]pipeline.Stage{replay}, p.Stages[2:]...) // drop discover and fetch
+
+	runtime.GC()
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	err := p.Run(&pipeline.Run{Started: start, Digest: &notify.Digest{}})
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return benchResult{
+		elapsed: elapsed,
+		allocs:  after.Mallocs - before.Mallocs,
+		bytes:   after.TotalAlloc - before.TotalAlloc,
+	}, err
+}
+
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	n := fs.Int("n", 1000, "number of synthetic game pages")
+	tiers := fs.Int("tiers", 12, "prize tiers per synthetic page")
+	dir := fs.String("pages", "", "replay the .html pages in this directory (e.g. the raw/ folder from -keep-artifacts) instead of synthetic ones")
+	runs := fs.Int("runs", 5, "number of timed passes")
+	concurrency := fs.Int("concurrency", runtime.GOMAXPROCS(0), "number of pages parsed at once")
+	seed := fs.Uint64("seed", 1, "seed for the synthetic pages")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	var pages []pipeline.Page
+	if *dir != "" {
+		var err error
+		if pages, err = recordedPages(*dir); err != nil {
+			return err
+		}
+	} else {
+		r := rand.New(rand.NewPCG(*seed, 0))
+		for i := range *n {
+			pages = append(pages, pipeline.Page{URL: fmt.Sprintf("https://www.mslottery.com/games/synthetic-%d/", i), Body: mslottotest.SyntheticPage(i, *tiers, r)})
+		}
+	}
+	var size int
+	for _, p := range pages {
+		size += len(p.Body)
+	}
+	fmt.Printf("Parsing and analyzing %d pages (%.1f KB) with concurrency %d\n", len(pages), float64(size)/1024, *concurrency)
+
+	var best benchResult
+	for i := range *runs {
+		res, err := benchPass(pages, *concurrency)
+		if err != nil {
+			return err
+		}
+		if i == 0 || res.elapsed < best.elapsed {
+			best = res
+		}
+		perPage := float64(len(pages))
+		fmt.Printf("  run %d: %8s  %8.0f pages/s  %7.0f allocs/page  %7.1f KB/page\n", i+1,
+			res.elapsed.Round(time.Microsecond), perPage/res.elapsed.Seconds(), float64(res.allocs)/perPage, float64(res.bytes)/1024/perPage)
+	}
+	fmt.Printf("Fastest: %s, %.0f pages/s\n", best.elapsed.Round(time.Microsecond), float64(len(pages))/best.elapsed.Seconds())
+	return nil
+}
@@ -0,0 +1,129 @@
+// Package cli is the mslotto command line as a cobra command tree.
+// cmd/mslotto executes NewCommand, and another Go program can mount the
+// same commands under one of its own, e.g. "multitool lotto explain 1234":
+//
+//	root.AddCommand(cli.NewCommand("lotto"))
+//
+// Commands parse their own single-dash flags with the flag package, so
+// cobra's flag parsing is off throughout, and they return their errors
+// rather than exiting; ExitCode turns one into the status mslotto exits
+// with.
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"msLotto/export"
+)
+
+// command is one mslotto subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+// commands lists every subcommand. scrape is also what runs when mslotto
+// gets no subcommand.
+var commands = []command{
+	{name: "scrape", summary: "scrape the active games and write the outputs", run: runScrape},
+	{name: "explain", summary: "walk through one game's EV", run: runExplain},
+	{name: "pack", summary: "simulate buying a full pack of one game", run: runPack},
+	{name: "simulate", summary: "simulate buying some tickets of one game", run: runSimulate},
+	{name: "optimize", summary: "pick the ticket mix that does best for a budget", run: runOptimize},
+	{name: "advise", summary: "suggest Kelly-sized stakes for a bankroll", run: runAdvise},
+	{name: "verdict", summary: "say whether today is a good day to buy", run: runVerdict},
+	{name: "report", summary: "write a self-contained HTML report", run: runReport},
+	{name: "diff", summary: "compare the last two runs recorded with -db", run: runDiff},
+	{name: "history", summary: "show how a game's prizes and EV moved across -db runs", run: runHistory},
+	{name: "runs", summary: "list the runs recorded with -db and their tags", run: runRuns},
+	{name: "tag", summary: "tag a run recorded with -db, e.g. before a price change", run: runTag},
+	{name: "export", summary: "write the runs recorded with -db over a date range as CSV, JSONL or Parquet", run: runExport},
+	{name: "backtest", summary: "replay -db runs to see how recommended games fared afterward", run: runBacktest},
+	{name: "serve", summary: "serve the -db run history's changes as JSON at /api/changes", run: runServe},
+	{name: "stats", summary: "summarize a snapshot journal", run: runStats},
+	{name: "lint-data", summary: "audit snapshot files for inconsistencies", run: runLintData},
+	{name: "json-patch", summary: "diff two snapshots as a JSON Patch", run: runJSONPatch},
+	{name: "debug-bundle", summary: "zip the artifacts kept by -keep-artifacts", run: runDebugBundle},
+	{name: "bench", summary: "time parsing and analysis over fixture pages", run: runBench},
+}
+
+// NewCommand returns the mslotto command tree named use. Without a
+// subcommand it scrapes, as mslotto always has; a first argument that is
+// neither a subcommand nor a flag is a usage error.
+func NewCommand(use string) *cobra.Command {
+	root := newCobraCommand(use, "rank the Mississippi Lottery's scratch-off games by expected value", runScrape)
+	root.Args = func(cmd *cobra.Command, args []string) error {
+		if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+			return usageError(fmt.Sprintf("unknown command %q for %q", args[0], cmd.CommandPath()))
+		}
+		return nil
+	}
+	root.AddCommand(Subcommands()...)
+	return root
+}
+
+// Subcommands returns each mslotto subcommand on its own, for a host
+// program that mounts them next to its own commands instead of under one.
+func Subcommands() []*cobra.Command {
+	cmds := make([]*cobra.Command, len(commands))
+	for i, c := range commands {
+		cmds[i] = newCobraCommand(c.name, c.summary, c.run)
+	}
+	return cmds
+}
+
+// newCobraCommand hands a command's arguments to run untouched. -h stops
+// after run prints its usage rather than failing.
+func newCobraCommand(use, summary string, run func(args []string) error) *cobra.Command {
+	return &cobra.Command{
+		Use:                use,
+		Short:              summary,
+		DisableFlagParsing: true,
+		SilenceUsage:       true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if err := run(args); !errors.Is(err, flag.ErrHelp) {
+				return err
+			}
+			return nil
+		},
+	}
+}
+
+// usageError is a mistake in how a command was called.
+type usageError string
+
+func (e usageError) Error() string { return string(e) }
+
+// parseFlags parses args into fs, which must be set to flag.ContinueOnError.
+// A bad flag is a usage error; fs has already printed what went wrong.
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil || errors.Is(err, flag.ErrHelp) {
+		return err
+	}
+	return usageError(err.Error())
+}
+
+// ExitCode is the status mslotto exits with for an error a command
+// returned: 2 for a usage mistake, as the flag package uses, 3 when a scrape
+// wrote some of its outputs but not others, so a cron job can tell that
+// apart from a run that produced nothing, and 1 otherwise.
+func ExitCode(err error) int {
+	var usage usageError
+	var outErr *export.OutputError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &usage):
+		return 2
+	case errors.As(err, &outErr) && outErr.Partial():
+		return 3
+	}
+	return 1
+}
@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"io"
+	"testing"
+)
+
+func TestNewCommandUsageErrors(t *testing.T) {
+	for _, args := range [][]string{{"bogus"}, {"explain"}, {"history", "-no-such-flag", "1"}, {"pack", "-trials", "0", "1000"},
+		{"export", "-model", "bogus"}, {"export", "-free-ticket", "bogus"}, {"export", "-merch-haircut", "150"}} {
+		cmd := NewCommand("mslotto")
+		cmd.SetArgs(args)
+		cmd.SetErr(io.Discard)
+		if code := ExitCode(cmd.Execute()); code != 2 {
+			t.Errorf("mslotto %q exits %d, want 2", args, code)
+		}
+	}
+}
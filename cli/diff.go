@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"msLotto/analyze"
+	"msLotto/store"
+)
+
+func runDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	asOf := fs.String("as-of", "", "compare the last run at or before this time, e.g. 2025-03-01, with the one before it")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := db.Runs()
+	if err != nil {
+		return err
+	}
+	if *asOf != "" {
+		t, err := parseAsOf(*asOf)
+		if err != nil {
+			return err
+		}
+		i, ok := runAsOf(runs, t)
+		if !ok {
+			return fmt.Errorf("%s has no run recorded at or before %s", *dbPath, t.Format("2006-01-02 15:04"))
+		}
+		runs = runs[i:]
+	}
+	if len(runs) < 2 {
+		return fmt.Errorf("%s has %d run(s) recorded; diff needs two", *dbPath, len(runs))
+	}
+	newer, err := db.LoadRun(runs[0])
+	if err != nil {
+		return err
+	}
+	older, err := db.LoadRun(runs[1])
+	if err != nil {
+		return err
+	}
+	analyze.WriteRunDiff(os.Stdout, analyze.DiffRuns(older, newer), runs[1], runs[0])
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"msLotto/analyze"
+)
+
+func runExplain(args []string) error {
+	fs := flag.NewFlagSet("explain", flag.ContinueOnError)
+	settingsOf := settingsFlags(fs)
+	asOf, dbPath := asOfFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("usage: mslotto explain [-model m] [-as-of time] <game number or name>")
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	games, err := analysisGames(*asOf, *dbPath, settings)
+	if err != nil {
+		return err
+	}
+	g, ok := analyze.FindGame(games, fs.Arg(0))
+	if !ok {
+		return fmt.Errorf("no game %q", fs.Arg(0))
+	}
+	analyze.WriteExplanation(os.Stdout, g)
+	return nil
+}
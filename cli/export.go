@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"msLotto/export"
+	"msLotto/model"
+	"msLotto/store"
+)
+
+// historyWriters are the formats export writes, by -format name.
+var historyWriters = map[string]func([]export.HistoryRun, string) error{
+	"csv":     export.WriteHistoryCSV,
+	"jsonl":   export.WriteHistoryJSONL,
+	"parquet": export.WriteHistoryParquet,
+}
+
+// parseRangeEnd reads the time named name, e.g. -from or -to, in any -as-of
+// layout. A bare date is the start of that day, or with end set the start
+// of the next, so -to takes in the whole day.
+func parseRangeEnd(name, s string, end bool) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range asOfLayouts {
+		t, err := time.ParseInLocation(layout, s, time.Local)
+		if err != nil {
+			continue
+		}
+		if end && layout == "2006-01-02" {
+			t = t.AddDate(0, 0, 1)
+		}
+		return t, nil
+	}
+	return time.Time{}, usageError(fmt.Sprintf("%s %q: want a date like 2025-03-01 or a time like 2025-03-01 18:00", name, s))
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	fromFlag := fs.String("from", "", "first day to export, e.g. 2025-01-01; default the first recorded run")
+	toFlag := fs.String("to", "", "last day to export, included; default the latest run")
+	format := fs.String("format", "csv", "file format: csv, jsonl or parquet")
+	out := fs.String("o", "", "file to write, default mslotto_history.<format>; .gz or .zst compresses csv and jsonl")
+	settingsOf := settingsFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	write, ok := historyWriters[*format]
+	if !ok {
+		return usageError(fmt.Sprintf("-format %q: want csv, jsonl or parquet", *format))
+	}
+	from, err := parseRangeEnd("-from", *fromFlag, false)
+	if err != nil {
+		return err
+	}
+	to, err := parseRangeEnd("-to", *toFlag, true)
+	if err != nil {
+		return err
+	}
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return usageError("-from must be before -to")
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = "mslotto_history." + *format
+	}
+
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := db.RunsBetween(from, to)
+	if err != nil {
+		return err
+	}
+	if len(runs) == 0 {
+		return fmt.Errorf("%s has no runs recorded in that range", *dbPath)
+	}
+	history := make([]export.HistoryRun, len(runs))
+	rows := 0
+	for i, r := range runs {
+		games, err := db.RunGames(r.Started)
+		if err != nil {
+			return err
+		}
+		model.ApplySettings(games, settings)
+		history[i] = export.HistoryRun{Started: r.Started, ID: r.ID, Games: games}
+		rows += len(games)
+	}
+	if err := write(history, *out); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Wrote %d games from %d runs to %s\n", rows, len(runs), *out)
+	return nil
+}
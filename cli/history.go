@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"msLotto/model"
+	"msLotto/store"
+)
+
+// tierLabel names a tier's column, e.g. "$500 WIN ALL".
+func tierLabel(p model.PrizeTier) string {
+	label := "$" + strconv.Itoa(p.Value)
+	if p.Tag != "" {
+		label += " " + p.Tag
+	}
+	return label
+}
+
+// writeGameHistory prints one row per observation with the EV, the parser
+// version that read it and every tier's remaining count, tiers as the
+// latest observation lists them.
+func writeGameHistory(w io.Writer, obs []store.Observation) error {
+	latest := obs[len(obs)-1].Game
+	fmt.Fprintf(w, "Game %d: %s ($%d ticket), %d observations\n", latest.GameNumber, latest.Name, latest.Price, len(obs))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Scraped\t%s\tPrizes Left\tParser\t", latest.EVSign().Label())
+	for _, p := range latest.PrizeTiers {
+		fmt.Fprintf(tw, "%s\t", tierLabel(p))
+	}
+	fmt.Fprintln(tw)
+	for _, o := range obs {
+		left := map[string]int{}
+		for _, p := range o.Game.PrizeTiers {
+			if _, dup := left[tierLabel(p)]; !dup {
+				left[tierLabel(p)] = p.RemainingCount
+			}
+		}
+		parser := "-"
+		if o.Game.ParserVersion != 0 {
+			parser = strconv.Itoa(o.Game.ParserVersion)
+		}
+		scraped := o.Scraped.Local().Format("2006-01-02 15:04")
+		if !o.Game.Ended.IsZero() {
+			scraped = "ended " + scraped
+		}
+		fmt.Fprintf(tw, "%s\t%.2f\t%d\t%s\t", scraped, o.Game.ReportedEV(), o.Game.TotalRemainingPrizes, parser)
+		for _, p := range latest.PrizeTiers {
+			if n, ok := left[tierLabel(p)]; ok {
+				fmt.Fprintf(tw, "%d\t", n)
+			} else {
+				fmt.Fprint(tw, "-\t")
+			}
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+func runHistory(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	settingsOf := settingsFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("usage: mslotto history [-db file] <game number>")
+	}
+	number, err := strconv.Atoi(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("game number %q: %v", fs.Arg(0), err)
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	obs, err := db.GameHistory(number)
+	if err != nil {
+		return err
+	}
+	if len(obs) == 0 {
+		return fmt.Errorf("game %d has not been recorded in %s", number, *dbPath)
+	}
+	for i := range obs {
+		obs[i].Game.Settings = settings
+	}
+	return writeGameHistory(os.Stdout, obs)
+}
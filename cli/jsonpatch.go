@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"msLotto/export"
+)
+
+func runJSONPatch(args []string) error {
+	if len(args) != 2 {
+		return usageError("usage: mslotto json-patch old.json new.json")
+	}
+	old, err := export.ReadJSONValue(args[0])
+	if err != nil {
+		return err
+	}
+	new, err := export.ReadJSONValue(args[1])
+	if err != nil {
+		return err
+	}
+	ops := export.JSONPatch(old, new)
+	if ops == nil {
+		ops = []export.PatchOp{}
+	}
+	out, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"msLotto/analyze"
+	"msLotto/export"
+)
+
+// runLintData prints every violation in the given snapshot files and fails
+// if there were any, so it can gate a CI pipeline.
+func runLintData(paths []string) error {
+	if len(paths) == 0 {
+		return usageError("usage: mslotto lint-data snapshot.json...")
+	}
+	var problems int
+	for _, path := range paths {
+		games, err := export.ReadGames(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			problems++
+			continue
+		}
+		for _, g := range games {
+			for _, p := range analyze.LintGame(g) {
+				fmt.Printf("%s: %s: %s\n", path, g.Name, p)
+				problems++
+			}
+		}
+	}
+	if problems > 0 {
+		return fmt.Errorf("%d problem(s) found", problems)
+	}
+	return nil
+}
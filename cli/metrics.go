@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"msLotto/pipeline"
+	"msLotto/scrape"
+)
+
+// cacheHitRate is the share of game pages that came back unchanged since
+// the last run, so parse reused what it made of them then.
+func cacheHitRate(r *pipeline.Run) float64 {
+	if len(r.Pages) == 0 {
+		return 0
+	}
+	return float64(r.Reused) / float64(len(r.Pages))
+}
+
+// writeMetrics writes the run's traffic to path in the Prometheus text
+// format, for node_exporter's textfile collector or a pushgateway. The file
+// is replaced whole so a scrape of it never sees half a run.
+func writeMetrics(path string, counter *scrape.CountingFetcher, r *pipeline.Run) error {
+	var b strings.Builder
+	metric := func(name, kind, help string, value any) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n%s %v\n", name, help, name, kind, name, value)
+	}
+	metric("mslotto_requests_total", "counter", "Requests the last run sent to the site.", counter.Requests())
+	metric("mslotto_request_errors_total", "counter", "Requests of the last run that failed.", counter.Errors())
+	metric("mslotto_downloaded_bytes_total", "counter", "Bytes the last run downloaded.", counter.Bytes())
+	metric("mslotto_response_seconds_total", "counter", "Time the last run spent waiting on responses.", counter.Waited().Seconds())
+	metric("mslotto_game_pages_total", "counter", "Game pages the last run fetched.", len(r.Pages))
+	metric("mslotto_game_page_cache_hits_total", "counter", "Game pages unchanged since the run before, reused without parsing.", r.Reused)
+	metric("mslotto_game_page_cache_hit_ratio", "gauge", "Share of the last run's game pages that were cache hits.", cacheHitRate(r))
+	metric("mslotto_last_run_timestamp_seconds", "gauge", "When the last run started, in Unix seconds.", r.Started.Unix())
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".mslotto-metrics-*")
+	if err != nil {
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if _, err := tmp.WriteString(b.String()); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"msLotto/pipeline"
+	"msLotto/scrape"
+)
+
+type staticFetcher []byte
+
+func (f staticFetcher) Fetch(string) ([]byte, error) { return f, nil }
+
+func TestWriteMetrics(t *testing.T) {
+	counter := &scrape.CountingFetcher{Next: staticFetcher("page")}
+	for range 4 {
+		counter.Fetch("https://www.mslottery.com/games/game/")
+	}
+	run := &pipeline.Run{Started: time.Unix(1740830400, 0), Pages: make([]pipeline.Page, 4), Reused: 1}
+	path := filepath.Join(t.TempDir(), "mslotto.prom")
+	if err := writeMetrics(path, counter, run); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"# TYPE mslotto_requests_total counter\nmslotto_requests_total 4\n",
+		"\nmslotto_downloaded_bytes_total 16\n",
+		"\nmslotto_game_page_cache_hits_total 1\n",
+		"\nmslotto_game_page_cache_hit_ratio 0.25\n",
+		"\nmslotto_last_run_timestamp_seconds 1740830400\n",
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("metrics missing %q:\n%s", want, data)
+		}
+	}
+}
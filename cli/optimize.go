@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"msLotto/analyze"
+)
+
+func runOptimize(args []string) error {
+	fs := flag.NewFlagSet("optimize", flag.ContinueOnError)
+	budget := fs.Int("budget", 100, "dollars to spend")
+	objective := fs.String("objective", analyze.MaxReturn, "what to maximize: "+analyze.MaxReturn+" (expected winnings) or "+analyze.MaxProfitChance+" (chance of winning back more than the spend)")
+	trials := fs.Int("trials", 20000, "simulated trials per ticket mix")
+	seed := fs.Uint64("seed", 0, "random seed, for repeatable results (0 picks one)")
+	includeDead := fs.Bool("include-dead", false, "consider games with no prizes left")
+	settingsOf := settingsFlags(fs)
+	asOf, dbPath := asOfFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *budget < 1 || *trials < 1 {
+		return errors.New("-budget and -trials must be at least 1")
+	}
+	if *objective != analyze.MaxReturn && *objective != analyze.MaxProfitChance {
+		return fmt.Errorf("-objective must be %s or %s", analyze.MaxReturn, analyze.MaxProfitChance)
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	games, err := analysisGames(*asOf, *dbPath, settings)
+	if err != nil {
+		return err
+	}
+	if *seed == 0 {
+		*seed = uint64(time.Now().UnixNano())
+	}
+	opts := analyze.OptimizeOptions{Budget: *budget, Objective: *objective, Trials: *trials, Seed: *seed, IncludeDead: *includeDead}
+	plan, d, err := analyze.Optimize(games, opts)
+	if err != nil {
+		return err
+	}
+	analyze.WriteOptimize(os.Stdout, plan, *budget, *objective, d)
+	return nil
+}
@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"msLotto/analyze"
+)
+
+func runPack(args []string) error {
+	fs := flag.NewFlagSet("pack", flag.ContinueOnError)
+	settingsOf := settingsFlags(fs)
+	size := fs.Int("size", 0, "tickets per pack, for games whose page doesn't list it")
+	trials := fs.Int("trials", 10000, "number of packs to simulate")
+	asOf, dbPath := asOfFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return usageError("usage: mslotto pack [-size n] [-trials n] [-as-of time] <game number or name>")
+	}
+	if *trials < 1 || *size < 0 {
+		return usageError("-trials must be at least 1 and -size can't be negative")
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	games, err := analysisGames(*asOf, *dbPath, settings)
+	if err != nil {
+		return err
+	}
+	g, ok := analyze.FindGame(games, fs.Arg(0))
+	if !ok {
+		return fmt.Errorf("no game %q", fs.Arg(0))
+	}
+	if *size == 0 {
+		*size = g.PackSize
+	}
+	if *size <= 0 {
+		return fmt.Errorf("%s: the game page doesn't list a pack size; give one with -size", g.Name)
+	}
+	rng := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+	analyze.WritePackReport(os.Stdout, g, analyze.SimulatePack(g, *size, *trials, rng))
+	return nil
+}
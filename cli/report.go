@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/export"
+	"msLotto/model"
+	"msLotto/scrape"
+	"msLotto/store"
+)
+
+// marketTrend works out the market's return per dollar at every run
+// recorded in the SQLite database at path up to until, or all of them when
+// until is zero, oldest first. It is nil when there is no database.
+func marketTrend(path string, until time.Time, settings *model.Settings) ([]analyze.MarketPoint, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil, nil
+	}
+	db, err := store.OpenSQLite(path)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	if !until.IsZero() {
+		until = until.Add(time.Nanosecond)
+	}
+	runs, err := db.RunsBetween(time.Time{}, until)
+	if err != nil {
+		return nil, err
+	}
+	var trend []analyze.MarketPoint
+	for _, r := range runs {
+		games, err := db.LoadRun(r.Started)
+		if err != nil {
+			return nil, err
+		}
+		model.ApplySettings(games, settings)
+		if ret, ok := analyze.MarketReturn(games); ok {
+			trend = append(trend, analyze.MarketPoint{At: r.Started, Return: ret})
+		}
+	}
+	return trend, nil
+}
+
+func runReport(args []string) error {
+	fs := flag.NewFlagSet("report", flag.ContinueOnError)
+	out := fs.String("out", "mslotto_report.html", "HTML file to write")
+	from := fs.String("from", "", "build the report from a JSON snapshot (-format json or a -layout per-game file) instead of scraping")
+	settingsOf := settingsFlags(fs)
+	sortKey := fs.String("sort", "best", "initial order of the games table: "+strings.Join(analyze.SortKeys(), ", "))
+	redact := fs.Bool("redact", false, "leave out URLs not on the lottery site, e.g. from a snapshot scraped through a mirror")
+	asOf, dbPath := asOfFlags(fs)
+	trend := fs.Bool("trend", true, "chart the market's return per dollar across the runs recorded in -db, up to -as-of")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	if *from != "" && *asOf != "" {
+		return errors.New("use either -from or -as-of, not both")
+	}
+	var games []model.Game
+	if *from != "" {
+		if games, err = export.ReadGames(*from); err != nil {
+			return err
+		}
+		model.ApplySettings(games, settings)
+	} else if games, err = analysisGames(*asOf, *dbPath, settings); err != nil {
+		return err
+	}
+	if err := analyze.SortBy(games, *sortKey, nil); err != nil {
+		return err
+	}
+	if *redact {
+		games = export.NewRedactor(scrape.StartURL).Games(games)
+	}
+	var points []analyze.MarketPoint
+	if *trend {
+		var until time.Time
+		if *asOf != "" {
+			if until, err = parseAsOf(*asOf); err != nil {
+				return err
+			}
+		}
+		if points, err = marketTrend(*dbPath, until, settings); err != nil {
+			return err
+		}
+	}
+	if err := export.WriteHTML(games, points, *out); err != nil {
+		return err
+	}
+	fmt.Println("Report written to", *out)
+	return nil
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"msLotto/store"
+)
+
+func runRuns(args []string) error {
+	fs := flag.NewFlagSet("runs", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	tag := fs.String("tag", "", "only list the runs with this tag")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := db.RunsBetween(time.Time{}, time.Time{})
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Started\tRun ID\tTags")
+	for _, r := range runs {
+		if *tag != "" && !slices.Contains(r.Tags, *tag) {
+			continue
+		}
+		id := r.ID
+		if id == "" {
+			id = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", r.Started.Local().Format("2006-01-02 15:04:05"), id, strings.Join(r.Tags, ", "))
+	}
+	return tw.Flush()
+}
+
+func runTag(args []string) error {
+	fs := flag.NewFlagSet("tag", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	asOf := fs.String("as-of", "", "tag the last run at or before this time, e.g. 2025-03-01 or \"2025-03-01 18:00\", instead of the latest")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageError("usage: mslotto tag [-db file] [-as-of time] <tag>...")
+	}
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := db.Runs()
+	if err != nil {
+		return err
+	}
+	i, ok := 0, len(runs) > 0
+	if *asOf != "" {
+		t, err := parseAsOf(*asOf)
+		if err != nil {
+			return err
+		}
+		i, ok = runAsOf(runs, t)
+	}
+	if !ok {
+		return fmt.Errorf("%s has no run to tag", *dbPath)
+	}
+	if err := db.TagRun(runs[i], fs.Args()); err != nil {
+		return err
+	}
+	fmt.Printf("Tagged the run of %s: %s\n", runs[i].Local().Format("2006-01-02 15:04"), strings.Join(fs.Args(), ", "))
+	return nil
+}
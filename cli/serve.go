@@ -0,0 +1,151 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/model"
+	"msLotto/store"
+)
+
+// changeEvent is one entry in the /api/changes feed: a game that appeared,
+// left the index or had prizes claimed in the run started At.
+type changeEvent struct {
+	At         time.Time   `json:"at"`
+	RunID      string      `json:"run_id,omitempty"`
+	Type       string      `json:"type"` // added, removed or changed
+	GameNumber int         `json:"game_number"`
+	Name       string      `json:"name"`
+	Price      int         `json:"price"`
+	EV         float64     `json:"ev"`
+	PreviousEV *float64    `json:"previous_ev,omitempty"` // changed games only
+	Ended      time.Time   `json:"ended,omitzero"`        // removed games whose final counts were recorded
+	Tiers      []tierDelta `json:"tiers,omitempty"`
+}
+
+// tierDelta is how many of one prize tier a changed game gave out.
+type tierDelta struct {
+	Value   int    `json:"value"`
+	Tag     string `json:"tag,omitempty"`
+	Claimed int    `json:"claimed"`
+	Left    int    `json:"left"`
+}
+
+// changesFeed is the /api/changes response. Until is the latest run it
+// covers, to pass back as since next time; it is since itself when no run
+// is newer.
+type changesFeed struct {
+	Until  time.Time     `json:"until"`
+	Events []changeEvent `json:"events"`
+}
+
+// runEvents turns the diff of one run against the run before it into
+// feed events.
+func runEvents(d analyze.RunDiff, run store.Run) []changeEvent {
+	event := func(kind string, g model.Game) changeEvent {
+		return changeEvent{At: run.Started, RunID: run.ID, Type: kind, GameNumber: g.GameNumber, Name: g.Name, Price: g.Price, EV: model.Round(g.ReportedEV(), 2)}
+	}
+	var events []changeEvent
+	for _, g := range d.Added {
+		events = append(events, event("added", g))
+	}
+	for _, g := range d.Removed {
+		e := event("removed", g)
+		e.Ended = g.Ended
+		events = append(events, e)
+	}
+	for _, c := range d.Changed {
+		e := event("changed", c.New)
+		prev := model.Round(c.Old.ReportedEV(), 2)
+		e.PreviousEV = &prev
+		for _, cl := range c.Claims {
+			e.Tiers = append(e.Tiers, tierDelta{Value: cl.Value, Tag: cl.Tag, Claimed: cl.Claimed, Left: cl.Left})
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+// changesSince diffs every run started after since against the run before
+// it. The first run recorded counts every game as added.
+func changesSince(db *store.SQLite, since time.Time, settings *model.Settings) (changesFeed, error) {
+	feed := changesFeed{Until: since, Events: []changeEvent{}}
+	runs, err := db.RunsBetween(time.Time{}, time.Time{})
+	if err != nil {
+		return feed, err
+	}
+	var prev []model.Game
+	for i, r := range runs {
+		if !r.Started.After(since) {
+			continue
+		}
+		if i > 0 && prev == nil {
+			if prev, err = db.LoadRun(runs[i-1].Started); err != nil {
+				return feed, err
+			}
+			model.ApplySettings(prev, settings)
+		}
+		games, err := db.LoadRun(r.Started)
+		if err != nil {
+			return feed, err
+		}
+		model.ApplySettings(games, settings)
+		feed.Events = append(feed.Events, runEvents(analyze.DiffRuns(prev, games), r)...)
+		feed.Until, prev = r.Started, games
+	}
+	return feed, nil
+}
+
+// changesHandler serves /api/changes?since=<time>, since in any -as-of
+// layout and left out for the whole history.
+func changesHandler(db *store.SQLite, settings *model.Settings) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		since, err := parseRangeEnd("since", r.URL.Query().Get("since"), false)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		feed, err := changesSince(db, since, settings)
+		if err != nil {
+			log.Println("Error reading run history:", err)
+			http.Error(w, "reading run history failed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(feed)
+	}
+}
+
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	dbPath := fs.String("db", "mslotto.db", "SQLite database runs were recorded in with scrape -db")
+	addr := fs.String("addr", "localhost:8080", "address to listen on")
+	settingsOf := settingsFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(*dbPath); err != nil {
+		return fmt.Errorf("no run history in %s; record runs with mslotto scrape -db %s", *dbPath, *dbPath)
+	}
+	db, err := store.OpenSQLite(*dbPath)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("GET /api/changes", changesHandler(db, settings))
+	srv := &http.Server{Addr: *addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	fmt.Fprintf(os.Stderr, "Serving %s on http://%s\n", *dbPath, *addr)
+	return srv.ListenAndServe()
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"msLotto/model"
+	"msLotto/store"
+)
+
+func TestChangesHandler(t *testing.T) {
+	db, err := store.OpenSQLite(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	game := func(number, remaining int) model.Game {
+		return model.Game{Name: "Game", Price: 2, Odds: 4, GameNumber: number, URL: "https://www.mslottery.com/games/game/",
+			PrizeTiers:          []model.PrizeTier{{Value: 100, OriginalCount: 10, RemainingCount: remaining}, {Value: 2, OriginalCount: 1000, RemainingCount: 900}},
+			TotalOriginalPrizes: 1010, TotalRemainingPrizes: remaining + 900}
+	}
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	if err := db.SaveRun(first, "run-1", []model.Game{game(1, 10), game(2, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveRun(second, "run-2", []model.Game{game(1, 7), game(3, 10)}); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(changesHandler(db, nil))
+	defer srv.Close()
+	get := func(query string) (changesFeed, int) {
+		t.Helper()
+		resp, err := http.Get(srv.URL + "/api/changes" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var feed changesFeed
+		if resp.StatusCode == http.StatusOK {
+			if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return feed, resp.StatusCode
+	}
+
+	feed, _ := get("?since=" + first.Format(time.RFC3339))
+	if !feed.Until.Equal(second) || len(feed.Events) != 3 {
+		t.Fatalf("feed since the first run = %+v, want 3 events until %v", feed, second)
+	}
+	kinds := map[int]string{}
+	for _, e := range feed.Events {
+		kinds[e.GameNumber] = e.Type
+		if e.RunID != "run-2" {
+			t.Errorf("event %+v not tagged run-2", e)
+		}
+		if e.Type == "changed" && (len(e.Tiers) != 1 || e.Tiers[0].Claimed != 3 || e.PreviousEV == nil) {
+			t.Errorf("changed event %+v, want 3 of the $100 tier claimed", e)
+		}
+	}
+	if want := map[int]string{1: "changed", 2: "removed", 3: "added"}; len(kinds) != 3 || kinds[1] != want[1] || kinds[2] != want[2] || kinds[3] != want[3] {
+		t.Errorf("event types %v, want %v", kinds, want)
+	}
+
+	if feed, _ := get(""); len(feed.Events) != 5 {
+		t.Errorf("whole history has %d events, want 5 (2 added, then 3)", len(feed.Events))
+	}
+	if feed, _ := get("?since=" + second.Format(time.RFC3339)); len(feed.Events) != 0 || !feed.Until.Equal(second) {
+		t.Errorf("feed since the latest run = %+v, want no events", feed)
+	}
+	if _, code := get("?since=yesterday"); code != http.StatusBadRequest {
+		t.Errorf("bad since answered %d, want 400", code)
+	}
+}
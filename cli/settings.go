@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	"msLotto/model"
+)
+
+// settingsFlags adds -model, -ev-sign and the prize valuation flags to a
+// command. The returned func, called after the flags are parsed, builds the
+// model.Settings they select; a bad value is a usage error.
+func settingsFlags(fs *flag.FlagSet) func() (*model.Settings, error) {
+	modelName := fs.String("model", "odds", "ticket estimation model: odds, printed or tier")
+	var prizeValues stringList
+	fs.Var(&prizeValues, "prize-value", "dollar value for a non-cash prize tier by its label, e.g. \"MOTORCYCLE=12000\" (repeatable)")
+	haircut := fs.Float64("merch-haircut", 0, "percent taken off the stated value of merchandise prizes")
+	freeTicket := fs.String("free-ticket", model.FreeTicketAtPrice, "value free-ticket prizes at the ticket's \"price\" or at the \"ev\" of the replacement ticket")
+	evSign := fs.String("ev-sign", "loss", "how EV is reported: \"loss\" (price - expected winnings, + is a loss) or \"return\" (expected winnings - price, + is a profit)")
+	return func() (*model.Settings, error) {
+		e, err := model.EstimatorByName(*modelName)
+		if err != nil {
+			return nil, usageError(err.Error())
+		}
+		if *freeTicket != model.FreeTicketAtPrice && *freeTicket != model.FreeTicketAtEV {
+			return nil, usageError(fmt.Sprintf("unknown -free-ticket %q", *freeTicket))
+		}
+		if *haircut < 0 || *haircut > 100 {
+			return nil, usageError(fmt.Sprintf("-merch-haircut %g: want a percent from 0 to 100", *haircut))
+		}
+		v := model.Valuation{Values: map[string]float64{}, Haircut: *haircut / 100, FreeTicket: *freeTicket}
+		for _, def := range prizeValues {
+			tag, value, err := model.ParsePrizeValue(def)
+			if err != nil {
+				return nil, usageError(err.Error())
+			}
+			v.Values[tag] = value
+		}
+		sign, err := model.EVSignByName(*evSign)
+		if err != nil {
+			return nil, usageError(err.Error())
+		}
+		return &model.Settings{Estimator: e, Valuation: v, EVSign: sign}, nil
+	}
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"os"
+	"time"
+
+	"msLotto/analyze"
+)
+
+func runSimulate(args []string) error {
+	fs := flag.NewFlagSet("simulate", flag.ContinueOnError)
+	game := fs.String("game", "", "game number or name to simulate")
+	tickets := fs.Int("tickets", 50, "tickets bought in each trial")
+	trials := fs.Int("trials", 100000, "number of trials")
+	seed := fs.Uint64("seed", 0, "random seed, for repeatable results (0 picks one)")
+	settingsOf := settingsFlags(fs)
+	asOf, dbPath := asOfFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if *game == "" && fs.NArg() == 1 {
+		*game = fs.Arg(0)
+	}
+	if *game == "" || fs.NArg() > 1 {
+		return usageError("usage: mslotto simulate -game <game number or name> [-tickets n] [-trials n] [-as-of time]")
+	}
+	if *tickets < 1 || *trials < 1 {
+		return errors.New("-tickets and -trials must be at least 1")
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	games, err := analysisGames(*asOf, *dbPath, settings)
+	if err != nil {
+		return err
+	}
+	g, ok := analyze.FindGame(games, *game)
+	if !ok {
+		return fmt.Errorf("no game %q", *game)
+	}
+	if g.Dead() {
+		log.Printf("Warning: %s has no prizes left; every ticket loses", g.Name)
+	}
+	if *seed == 0 {
+		*seed = uint64(time.Now().UnixNano())
+	}
+	rng := rand.New(rand.NewPCG(*seed, 0))
+	analyze.WriteSimulateReport(os.Stdout, g, *tickets, analyze.Simulate(g, *tickets, *trials, rng))
+	return nil
+}
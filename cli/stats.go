@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"flag"
+	"os"
+
+	"msLotto/journal"
+)
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	dir := fs.String("dir", "mslotto_games", "snapshot journal written by -layout per-game -git-commit")
+	top := fs.Int("top", 5, "number of EV swings and stable games to list")
+	k := fs.Int("k", 10, "number of recent snapshots used for rank stability")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	snaps, err := journal.ReadJournal(*dir)
+	if err != nil {
+		return err
+	}
+	journal.WriteStats(os.Stdout, snaps, *top)
+	if len(snaps) > 0 {
+		journal.WriteRankStability(os.Stdout, journal.RankVolatility(snaps, *k), *top)
+		journal.WriteFamilies(os.Stdout, journal.Families(snaps))
+	}
+	return nil
+}
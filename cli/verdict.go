@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/model"
+	"msLotto/store"
+)
+
+func runVerdict(args []string) error {
+	fs := flag.NewFlagSet("verdict", flag.ContinueOnError)
+	settingsOf := settingsFlags(fs)
+	asOf, dbPath := asOfFlags(fs)
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	settings, err := settingsOf()
+	if err != nil {
+		return err
+	}
+
+	history, err := bestReturns(*dbPath, *asOf, settings)
+	if err != nil {
+		return err
+	}
+	games, err := analysisGames(*asOf, *dbPath, settings)
+	if err != nil {
+		return err
+	}
+	v, ok := analyze.JudgeDay(games, history)
+	if !ok {
+		return errors.New("no game has prizes left")
+	}
+	fmt.Println(v.Summary())
+	return nil
+}
+
+// bestReturns reads the best return per dollar of every run recorded in the
+// database at dbPath before the one being judged: the latest run as of asOf,
+// or today's scrape, worked out under settings. A missing database is no
+// history.
+func bestReturns(dbPath, asOf string, settings *model.Settings) ([]float64, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, nil
+	}
+	db, err := store.OpenSQLite(dbPath)
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	runs, err := db.Runs()
+	if err != nil {
+		return nil, err
+	}
+	if asOf != "" {
+		t, err := parseAsOf(asOf)
+		if err != nil {
+			return nil, err
+		}
+		i, ok := runAsOf(runs, t)
+		if !ok {
+			return nil, fmt.Errorf("%s has no run recorded at or before %s", dbPath, t.Format("2006-01-02 15:04"))
+		}
+		runs = runs[i+1:]
+	}
+
+	var best []float64
+	for _, started := range runs {
+		games, err := db.LoadRun(started)
+		if err != nil {
+			return nil, fmt.Errorf("run of %s: %w", started.Local().Format(time.DateTime), err)
+		}
+		model.ApplySettings(games, settings)
+		if _, r, ok := analyze.BestReturn(analyze.Listed(games)); ok {
+			best = append(best, r)
+		}
+	}
+	return best, nil
+}
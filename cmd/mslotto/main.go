@@ -0,0 +1,15 @@
+// Command mslotto scrapes the Mississippi Lottery's active scratch-off games
+// and ranks them by expected value.
+package main
+
+import (
+	"os"
+
+	"msLotto/cli"
+)
+
+func main() {
+	if err := cli.NewCommand("mslotto").Execute(); err != nil {
+		os.Exit(cli.ExitCode(err))
+	}
+}
@@ -0,0 +1,102 @@
+package export
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressionExts maps each -compress codec to the extension that marks a
+// file compressed with it.
+var compressionExts = map[string]string{
+	"gzip": ".gz",
+	"zstd": ".zst",
+}
+
+// CompressionExt is the file extension for codec, "" for none.
+func CompressionExt(codec string) (string, error) {
+	if codec == "" {
+		return "", nil
+	}
+	ext, ok := compressionExts[codec]
+	if !ok {
+		return "", fmt.Errorf("unknown compression %q; want gzip or zstd", codec)
+	}
+	return ext, nil
+}
+
+// TrimCompressionExt strips a compression extension from path, reporting
+// whether it had one.
+func TrimCompressionExt(path string) (string, bool) {
+	for _, ext := range compressionExts {
+		if base, ok := strings.CutSuffix(path, ext); ok {
+			return base, true
+		}
+	}
+	return path, false
+}
+
+// compressedFile closes the compressor, flushing it, before the file.
+type compressedFile struct {
+	io.WriteCloser
+	file *os.File
+}
+
+func (f compressedFile) Close() error {
+	err := f.WriteCloser.Close()
+	if cerr := f.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// CreateFile creates filename, compressing what is written to it when its
+// extension is .gz or .zst. The file is only complete once closed.
+func CreateFile(filename string) (io.WriteCloser, error) {
+	file, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case strings.HasSuffix(filename, compressionExts["gzip"]):
+		return compressedFile{WriteCloser: gzip.NewWriter(file), file: file}, nil
+	case strings.HasSuffix(filename, compressionExts["zstd"]):
+		zw, err := zstd.NewWriter(file)
+		if err != nil {
+			file.Close()
+			return nil, err
+		}
+		return compressedFile{WriteCloser: zw, file: file}, nil
+	}
+	return file, nil
+}
+
+// readFile reads path, decompressing it when its extension is .gz or .zst.
+func readFile(path string) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	switch {
+	case strings.HasSuffix(path, compressionExts["gzip"]):
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		r = zr
+	case strings.HasSuffix(path, compressionExts["zstd"]):
+		zr, err := zstd.NewReader(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	return io.ReadAll(r)
+}
@@ -0,0 +1,198 @@
+// Package export writes scraped games out: CSV, JSON and JSONL, Parquet,
+// Excel, Markdown, terminal tables, the HTML report and Google Sheets rows,
+// plus the run history the export command dumps.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"msLotto/analyze"
+	"msLotto/model"
+)
+
+// Options add a run's own fields to what the outputs write.
+type Options struct {
+	// RunID, when set, tags the JSON, JSONL, Parquet, per-game Markdown and
+	// Google Sheets rows with the run that wrote them.
+	RunID string
+	// Columns are added to every output, e.g. the -column definitions for
+	// a run in flag order: as named fields of the JSON, Parquet and
+	// per-game Markdown records and as trailing columns everywhere else.
+	Columns []analyze.ComputedColumn
+	// Peers rank each game's price percentile, e.g. with journaled
+	// snapshots; nil ranks the games being written against each other.
+	Peers analyze.PricePeers
+	// Claims measure each game's claim rate and projected sell-out, e.g.
+	// against the -db history; nil leaves both blank.
+	Claims *analyze.ClaimHistory
+}
+
+// ranked returns o with Peers set, from games when the caller set none.
+func (o Options) ranked(games []model.Game) Options {
+	if o.Peers == nil {
+		o.Peers = analyze.NewPricePeers(games, nil)
+	}
+	return o
+}
+
+// evSign is how the games report EV, which all of a run's games share.
+func evSign(games []model.Game) model.EVSign {
+	if len(games) == 0 {
+		return model.NetLoss
+	}
+	return games[0].EVSign()
+}
+
+var csvHeader = []string{"Name", "Price", "Odds", "Launch Date", "Original Winning Tickets", "Remaining Winning Tickets", "Estimated Original Tickets", "Estimated Remaining Tickets", "Original Prize Money", "Remaining Prize Money", "Payout Remaining", "EV", "Return Per Ticket", "Profit Chance", "Top Prizes Left", "EV Without Top Prize", "Annualized Return", "Break-even Extra Low Prizes", "Break-even Top Prizes", "Price Percentile", "Claims Per Day", "Projected Sell-out", "New", "Ended", "Last Updated", "URL"}
+
+// csvHeaderRow is csvHeader, with EV labelled by sign, followed by any
+// computed columns.
+func (o Options) csvHeaderRow(sign model.EVSign) []string {
+	row := append([]string(nil), csvHeader...)
+	row[slices.Index(row, "EV")] = sign.Label()
+	for _, c := range o.Columns {
+		row = append(row, c.Name)
+	}
+	return row
+}
+
+func (o Options) csvRow(g model.Game) []string {
+	row := []string{
+		g.Name,
+		strconv.Itoa(g.Price),
+		fmt.Sprintf("1:%.2f", g.Odds),
+		g.LaunchDate,
+		strconv.Itoa(g.TotalOriginalPrizes),
+		strconv.Itoa(g.TotalRemainingPrizes),
+		strconv.Itoa(g.OriginalTickets()),
+		strconv.Itoa(g.RemainingTickets()),
+		strconv.Itoa(g.OriginalPrizeMoney()),
+		strconv.Itoa(g.RemainingPrizeMoney()),
+		fmt.Sprintf("%.4f", g.PayoutRemaining()),
+		fmt.Sprintf("%.2f", g.ReportedEV()),
+		fmt.Sprintf("%.4f", g.ReturnRate()),
+		fmt.Sprintf("%.4f", g.ProfitChance()),
+		strconv.Itoa(g.TopTier().RemainingCount),
+		fmt.Sprintf("%.2f", g.EVSign().Apply(g.EVWithoutTopPrize())),
+		fmt.Sprintf("%.4f", g.AnnualizedReturn()),
+		strconv.Itoa(g.BreakEvenLowPrizes()),
+		strconv.Itoa(g.BreakEvenTopPrizes()),
+		fmt.Sprintf("%.0f", o.Peers.Percentile(g)),
+		o.claimsPerDay(g),
+		o.sellOut(g),
+		newFlag(g),
+		ended(g),
+		lastUpdated(g),
+		g.URL,
+	}
+	for _, c := range o.Columns {
+		row = append(row, fmt.Sprintf("%.4f", c.Eval(g)))
+	}
+	return row
+}
+
+// computed is g's Columns by name, rounded, or nil without any.
+func (o Options) computed(g model.Game) map[string]float64 {
+	if len(o.Columns) == 0 {
+		return nil
+	}
+	computed := make(map[string]float64, len(o.Columns))
+	for _, c := range o.Columns {
+		computed[c.Name] = model.Round(c.Eval(g), 4)
+	}
+	return computed
+}
+
+// claimsPerDay formats the claim rate, blank without enough history.
+func (o Options) claimsPerDay(g model.Game) string {
+	rate, ok := o.Claims.ClaimsPerDay(g)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%.1f", rate)
+}
+
+// sellOut formats the projected sell-out date, blank when there is none.
+func (o Options) sellOut(g model.Game) string {
+	date, ok := o.Claims.SellOutDate(g)
+	if !ok {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+// newFlag marks a game first seen this run.
+func newFlag(g model.Game) string {
+	if g.New {
+		return "yes"
+	}
+	return ""
+}
+
+// ended formats when an archived game ended, blank for active games.
+func ended(g model.Game) string {
+	if g.Ended.IsZero() {
+		return ""
+	}
+	return g.Ended.Local().Format("2006-01-02")
+}
+
+// lastUpdated formats the site's update date, blank when the page didn't show one.
+func lastUpdated(g model.Game) string {
+	if g.LastUpdated.IsZero() {
+		return ""
+	}
+	return g.LastUpdated.Format("2006-01-02")
+}
+
+// WriteCSV writes one row per game, compressed when filename ends in .gz or
+// .zst.
+func WriteCSV(games []model.Game, filename string, opts Options) error {
+	opts = opts.ranked(games)
+	file, err := CreateFile(filename)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	w.Write(opts.csvHeaderRow(evSign(games)))
+	for _, g := range games {
+		w.Write(opts.csvRow(g))
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteGroupedCSV writes a titled section with its own header row per price.
+func WriteGroupedCSV(games []model.Game, filename string, opts Options) error {
+	opts = opts.ranked(games)
+	file, err := CreateFile(filename)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	for i, group := range analyze.GroupByPrice(games) {
+		if i > 0 {
+			w.Write([]string{""})
+		}
+		w.Write([]string{fmt.Sprintf("$%d Tickets", group[0].Price)})
+		w.Write(opts.csvHeaderRow(evSign(group)))
+		for _, g := range group {
+			w.Write(opts.csvRow(g))
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,100 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"msLotto/model"
+)
+
+// HistoryRun is one recorded run's games, for the history writers.
+type HistoryRun struct {
+	Started time.Time
+	ID      string
+	Games   []model.Game
+}
+
+// historyRecord is a gameRecord tagged with the run that saw it.
+type historyRecord struct {
+	ScrapedAt time.Time `json:"scraped_at"`
+	gameRecord
+}
+
+// WriteHistoryCSV writes every run's games as rows of one CSV file, each led
+// by when its run scraped it and the run's ID. Price percentiles rank each
+// game against its own run.
+func WriteHistoryCSV(runs []HistoryRun, filename string) error {
+	file, err := CreateFile(filename)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(file)
+	var sign model.EVSign
+	for _, r := range runs {
+		if len(r.Games) > 0 {
+			sign = evSign(r.Games)
+			break
+		}
+	}
+	w.Write(append([]string{"Scraped At", "Run ID"}, Options{}.csvHeaderRow(sign)...))
+	for _, r := range runs {
+		opts := Options{RunID: r.ID}.ranked(r.Games)
+		at := r.Started.UTC().Format(time.RFC3339)
+		for _, g := range r.Games {
+			w.Write(append([]string{at, r.ID}, opts.csvRow(g)...))
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WriteHistoryJSONL writes every run's games one per line, as the JSON
+// outputs write them plus scraped_at.
+func WriteHistoryJSONL(runs []HistoryRun, filename string) error {
+	file, err := CreateFile(filename)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(file)
+	for _, r := range runs {
+		opts := Options{RunID: r.ID}.ranked(r.Games)
+		for _, g := range r.Games {
+			if err := enc.Encode(historyRecord{ScrapedAt: r.Started.UTC(), gameRecord: opts.newGameRecord(g)}); err != nil {
+				file.Close()
+				return err
+			}
+		}
+	}
+	return file.Close()
+}
+
+// WriteHistoryParquet writes every run's games to filename and their prize
+// tiers to TiersFile(filename), as WriteParquet does, with scraped_at set.
+func WriteHistoryParquet(runs []HistoryRun, filename string) error {
+	var rows []parquetGame
+	var tiers []parquetTier
+	for _, r := range runs {
+		at := r.Started.UTC()
+		rs, ts := Options{RunID: r.ID}.ranked(r.Games).parquetRows(r.Games)
+		for i := range rs {
+			rs[i].ScrapedAt = &at
+		}
+		for i := range ts {
+			ts[i].ScrapedAt = &at
+		}
+		rows = append(rows, rs...)
+		tiers = append(tiers, ts...)
+	}
+	if err := parquet.WriteFile(filename, rows); err != nil {
+		return err
+	}
+	return parquet.WriteFile(TiersFile(filename), tiers)
+}
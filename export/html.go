@@ -0,0 +1,150 @@
+package export
+
+import (
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/skip2/go-qrcode"
+
+	"msLotto/analyze"
+	"msLotto/model"
+)
+
+//go:embed report.html.tmpl
+var reportTemplate string
+
+var reportTmpl = template.Must(template.New("report").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+	"pct": func(f float64) float64 { return f * 100 },
+}).Parse(reportTemplate))
+
+// reportGame is what the report template shows for one game.
+type reportGame struct {
+	model.Game
+	EV               float64
+	ReturnPerDollar  float64
+	PayoutRemaining  float64
+	ProfitChance     float64
+	PricePercentile  float64
+	Profitable       bool
+	TopLeft          int
+	TopOriginal      int
+	EVWithoutTop     float64
+	OriginalTickets  int
+	RemainingTickets int
+	LastUpdated      string
+	QRCode           template.URL // PNG data URI linking to the game page
+	Tiers            []tierRecord
+	Buckets          []analyze.BucketTotal
+}
+
+// qrCode returns a QR code for url as a PNG data URI, or "" when there is no
+// url, e.g. after -redact dropped it.
+func qrCode(url string) (template.URL, error) {
+	if url == "" {
+		return "", nil
+	}
+	png, err := qrcode.Encode(url, qrcode.Medium, 128)
+	if err != nil {
+		return "", err
+	}
+	return template.URL("data:image/png;base64," + base64.StdEncoding.EncodeToString(png)), nil
+}
+
+// Size of the market trend chart's drawing area, in SVG units.
+const chartWidth, chartHeight = 600.0, 180.0
+
+// trendChart is the market's return per dollar over time as an SVG
+// polyline.
+type trendChart struct {
+	Points    string // "x,y x,y ..." within chartWidth by chartHeight
+	Low, High float64
+	From, To  time.Time
+	Latest    float64
+}
+
+// newTrendChart plots trend, oldest first. It is nil with fewer than two
+// points, which make no line.
+func newTrendChart(trend []analyze.MarketPoint) *trendChart {
+	if len(trend) < 2 {
+		return nil
+	}
+	c := &trendChart{Low: trend[0].Return, High: trend[0].Return, From: trend[0].At, To: trend[len(trend)-1].At, Latest: trend[len(trend)-1].Return}
+	for _, p := range trend {
+		c.Low, c.High = min(c.Low, p.Return), max(c.High, p.Return)
+	}
+	if c.High-c.Low < 0.01 {
+		mid := (c.High + c.Low) / 2
+		c.Low, c.High = mid-0.005, mid+0.005
+	}
+	span := c.To.Sub(c.From).Seconds()
+	points := make([]string, len(trend))
+	for i, p := range trend {
+		x := chartWidth * float64(i) / float64(len(trend)-1)
+		if span > 0 {
+			x = chartWidth * p.At.Sub(c.From).Seconds() / span
+		}
+		y := chartHeight * (c.High - p.Return) / (c.High - c.Low)
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	c.Points = strings.Join(points, " ")
+	return c
+}
+
+// WriteHTML writes a self-contained HTML report: a sortable table of the
+// games in the order given, then each game's prize breakdown with a QR code
+// for its game page, handy for pulling a pick up on a phone. With two or
+// more points of trend, oldest first, it also charts the market's return
+// per dollar over time.
+func WriteHTML(games []model.Game, trend []analyze.MarketPoint, filename string) error {
+	data := struct {
+		Generated time.Time
+		EVLabel   string
+		Games     []reportGame
+		Buckets   []analyze.BucketTotal
+		Trend     *trendChart
+	}{Generated: time.Now(), EVLabel: evSign(games).Label(), Buckets: analyze.RemainingByBucket(games...), Trend: newTrendChart(trend)}
+
+	opts := Options{}.ranked(games)
+	for _, g := range games {
+		rec := opts.newGameRecord(g)
+		top := g.TopTier()
+		qr, err := qrCode(g.URL)
+		if err != nil {
+			return err
+		}
+		data.Games = append(data.Games, reportGame{
+			Game:             g,
+			EV:               rec.EV,
+			ReturnPerDollar:  analyze.ReturnPerDollar(g.Price, g.EV()),
+			PayoutRemaining:  rec.PayoutRemaining,
+			ProfitChance:     rec.ProfitChance,
+			PricePercentile:  rec.PricePercentile,
+			Profitable:       g.EV() < 0,
+			TopLeft:          top.RemainingCount,
+			TopOriginal:      top.OriginalCount,
+			EVWithoutTop:     rec.EVWithoutTopPrize,
+			OriginalTickets:  rec.EstimatedOriginalTickets,
+			RemainingTickets: rec.EstimatedRemainingTickets,
+			LastUpdated:      lastUpdated(g),
+			QRCode:           qr,
+			Tiers:            rec.PrizeTiers,
+			Buckets:          analyze.RemainingByBucket(g),
+		})
+	}
+
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	if err := reportTmpl.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,166 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"msLotto/journal"
+	"msLotto/model"
+)
+
+// gameRecord is a Game as written to JSON, with the derived metrics next to
+// the scraped fields so consumers don't have to recompute them.
+type gameRecord struct {
+	model.Game
+	PrizeTiers                []tierRecord       `json:"prize_tiers"`
+	EstimatedOriginalTickets  int                `json:"estimated_original_tickets"`
+	EstimatedRemainingTickets int                `json:"estimated_remaining_tickets"`
+	OriginalPrizeMoney        int                `json:"original_prize_money"`
+	RemainingPrizeMoney       int                `json:"remaining_prize_money"`
+	PayoutRemaining           float64            `json:"payout_remaining"`
+	EV                        float64            `json:"ev"`
+	EVSign                    string             `json:"ev_sign"`
+	ReturnRate                float64            `json:"return_per_ticket"`
+	ProfitChance              float64            `json:"profit_chance"`
+	TopPrizesLeft             int                `json:"top_prizes_left"`
+	EVWithoutTopPrize         float64            `json:"ev_without_top_prize"`
+	AnnualizedReturn          float64            `json:"annualized_return"`
+	BreakEvenLowPrizes        int                `json:"break_even_extra_low_prizes"`
+	BreakEvenTopPrizes        int                `json:"break_even_top_prizes"`
+	PricePercentile           float64            `json:"price_percentile"`
+	ClaimsPerDay              *float64           `json:"claims_per_day,omitempty"`
+	ProjectedSellOut          string             `json:"projected_sell_out,omitempty"`
+	Computed                  map[string]float64 `json:"computed,omitempty"`
+	RunID                     string             `json:"run_id,omitempty"`
+}
+
+// tierRecord adds the derived per-tier odds, which the site rarely publishes.
+type tierRecord struct {
+	model.PrizeTier
+	OriginalOdds float64 `json:"original_odds"`
+	CurrentOdds  float64 `json:"current_odds"`
+}
+
+func (o Options) newGameRecord(g model.Game) gameRecord {
+	tiers := make([]tierRecord, len(g.PrizeTiers))
+	for i, p := range g.PrizeTiers {
+		orig, cur := g.TierOdds(p)
+		tiers[i] = tierRecord{PrizeTier: p, OriginalOdds: model.Round(orig, 2), CurrentOdds: model.Round(cur, 2)}
+	}
+	var claims *float64
+	if rate, ok := o.Claims.ClaimsPerDay(g); ok {
+		rate = model.Round(rate, 1)
+		claims = &rate
+	}
+	return gameRecord{
+		Game:                      g,
+		ClaimsPerDay:              claims,
+		ProjectedSellOut:          o.sellOut(g),
+		Computed:                  o.computed(g),
+		RunID:                     o.RunID,
+		PrizeTiers:                tiers,
+		EstimatedOriginalTickets:  g.OriginalTickets(),
+		EstimatedRemainingTickets: g.RemainingTickets(),
+		OriginalPrizeMoney:        g.OriginalPrizeMoney(),
+		RemainingPrizeMoney:       g.RemainingPrizeMoney(),
+		PayoutRemaining:           model.Round(g.PayoutRemaining(), 4),
+		EV:                        model.Round(g.ReportedEV(), 2),
+		EVSign:                    g.EVSign().Name(),
+		ReturnRate:                model.Round(g.ReturnRate(), 4),
+		ProfitChance:              model.Round(g.ProfitChance(), 4),
+		TopPrizesLeft:             g.TopTier().RemainingCount,
+		EVWithoutTopPrize:         model.Round(g.EVSign().Apply(g.EVWithoutTopPrize()), 2),
+		AnnualizedReturn:          model.Round(g.AnnualizedReturn(), 4),
+		BreakEvenLowPrizes:        g.BreakEvenLowPrizes(),
+		BreakEvenTopPrizes:        g.BreakEvenTopPrizes(),
+		PricePercentile:           model.Round(o.Peers.Percentile(g), 0),
+	}
+}
+
+// gameFileName names a game's file by its game number, falling back to the
+// URL slug for pages that don't show one.
+func gameFileName(g model.Game) string {
+	if g.GameNumber != 0 {
+		return fmt.Sprintf("%d.json", g.GameNumber)
+	}
+	return strings.ReplaceAll(g.Name, " ", "-") + ".json"
+}
+
+// WritePerGameJSON writes one file per game plus an index.json listing them
+// in the order given.
+func WritePerGameJSON(games []model.Game, dir string, opts Options) error {
+	opts = opts.ranked(games)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	index := make([]journal.IndexEntry, 0, len(games))
+	for _, g := range games {
+		name := gameFileName(g)
+		if err := WriteJSONFile(filepath.Join(dir, name), opts.newGameRecord(g)); err != nil {
+			return err
+		}
+		index = append(index, journal.IndexEntry{
+			GameNumber:  g.GameNumber,
+			Name:        g.Name,
+			Price:       g.Price,
+			EV:          model.Round(g.EV(), 2),
+			File:        name,
+			LastUpdated: g.LastUpdated,
+		})
+	}
+	return WriteJSONFile(filepath.Join(dir, "index.json"), index)
+}
+
+// WriteJSON writes every game, with its prize tiers and derived metrics, as
+// one JSON array in the order given.
+func WriteJSON(games []model.Game, filename string, opts Options) error {
+	opts = opts.ranked(games)
+	records := make([]gameRecord, len(games))
+	for i, g := range games {
+		records[i] = opts.newGameRecord(g)
+	}
+	return WriteJSONFile(filename, records)
+}
+
+// WriteJSONFile writes v as indented JSON, compressed when path ends in .gz
+// or .zst.
+func WriteJSONFile(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := CreateFile(path)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ReadGames accepts either a single game object, as written by -layout
+// per-game, or an array of games, optionally gzip or zstd compressed.
+func ReadGames(path string) ([]model.Game, error) {
+	data, err := readFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "[") {
+		var games []model.Game
+		if err := json.Unmarshal(data, &games); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return games, nil
+	}
+	var g model.Game
+	if err := json.Unmarshal(data, &g); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return []model.Game{g}, nil
+}
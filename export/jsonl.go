@@ -0,0 +1,41 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+
+	"msLotto/model"
+)
+
+// streamedRecord is a gameRecord written before the run is complete, so it
+// leaves out the price percentile, which needs every game to rank against.
+type streamedRecord struct {
+	gameRecord
+	PricePercentile *float64 `json:"price_percentile,omitempty"`
+}
+
+// JSONLStream writes one game per line as games are parsed, so jq or a log
+// shipper can follow a run instead of waiting for it to finish.
+type JSONLStream struct {
+	enc  *json.Encoder
+	opts Options
+	err  error
+}
+
+func NewJSONLStream(w io.Writer, opts Options) *JSONLStream {
+	return &JSONLStream{enc: json.NewEncoder(w), opts: opts}
+}
+
+// Add writes g as one line. After a failed write it does nothing more; Err
+// reports the failure.
+func (s *JSONLStream) Add(g model.Game) {
+	if s.err != nil {
+		return
+	}
+	s.err = s.enc.Encode(streamedRecord{gameRecord: s.opts.newGameRecord(g)})
+}
+
+// Err is the first write error, if any.
+func (s *JSONLStream) Err() error {
+	return s.err
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// PatchOp is one RFC 6902 JSON Patch operation.
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value"`
+}
+
+// MarshalJSON leaves "value" off remove operations only, so a replace with
+// null still carries its value.
+func (op PatchOp) MarshalJSON() ([]byte, error) {
+	if op.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{op.Op, op.Path})
+	}
+	type plain PatchOp
+	return json.Marshal(plain(op))
+}
+
+// JSONPatch returns the operations that turn old into new. Both are decoded
+// JSON values (maps, slices, scalars). Array elements are compared by
+// position, which suits snapshots whose order is already deterministic.
+func JSONPatch(old, new any) []PatchOp {
+	var ops []PatchOp
+	diffJSON("", old, new, &ops)
+	return ops
+}
+
+func diffJSON(path string, old, new any, ops *[]PatchOp) {
+	switch o := old.(type) {
+	case map[string]any:
+		n, ok := new.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(o)+len(n))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		for k := range n {
+			if _, seen := o[k]; !seen {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			p := path + "/" + escapePointer(k)
+			ov, inOld := o[k]
+			nv, inNew := n[k]
+			switch {
+			case !inNew:
+				*ops = append(*ops, PatchOp{Op: "remove", Path: p})
+			case !inOld:
+				*ops = append(*ops, PatchOp{Op: "add", Path: p, Value: nv})
+			default:
+				diffJSON(p, ov, nv, ops)
+			}
+		}
+		return
+	case []any:
+		n, ok := new.([]any)
+		if !ok {
+			break
+		}
+		common := min(len(o), len(n))
+		for i := 0; i < common; i++ {
+			diffJSON(path+"/"+strconv.Itoa(i), o[i], n[i], ops)
+		}
+		for i := common; i < len(n); i++ {
+			*ops = append(*ops, PatchOp{Op: "add", Path: path + "/-", Value: n[i]})
+		}
+		// Remove from the end so earlier indexes stay valid.
+		for i := len(o) - 1; i >= common; i-- {
+			*ops = append(*ops, PatchOp{Op: "remove", Path: path + "/" + strconv.Itoa(i)})
+		}
+		return
+	}
+	if !reflect.DeepEqual(old, new) {
+		*ops = append(*ops, PatchOp{Op: "replace", Path: path, Value: new})
+	}
+}
+
+// escapePointer escapes a key for use in a JSON Pointer (RFC 6901).
+func escapePointer(k string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(k)
+}
+
+// ReadJSONValue decodes the JSON file at path into maps, slices and scalars.
+func ReadJSONValue(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return v, nil
+}
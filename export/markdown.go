@@ -0,0 +1,136 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"msLotto/model"
+)
+
+// WriteMarkdown writes the games, in the order given, as a GitHub-flavored
+// markdown table for pasting into an issue or report, with opts.Columns
+// after the built-in columns.
+func WriteMarkdown(games []model.Game, filename string, opts Options) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "| Rank | Name | Price | %s | Profit Chance | Prizes Remaining |", evSign(games).Label())
+	for _, c := range opts.Columns {
+		fmt.Fprintf(&b, " %s |", markdownEscape(c.Name))
+	}
+	b.WriteString("\n| ---: | --- | ---: | ---: | ---: | ---: |")
+	b.WriteString(strings.Repeat(" ---: |", len(opts.Columns)))
+	b.WriteString("\n")
+	for i, g := range games {
+		remaining := "n/a"
+		if g.TotalOriginalPrizes > 0 {
+			remaining = fmt.Sprintf("%.1f%%", 100*float64(g.TotalRemainingPrizes)/float64(g.TotalOriginalPrizes))
+		}
+		name := markdownEscape(g.Name)
+		switch {
+		case g.New:
+			name += " **NEW**"
+		case !g.Ended.IsZero():
+			name += " (ended " + ended(g) + ")"
+		}
+		fmt.Fprintf(&b, "| %d | %s | $%d | %.2f | %.2f%% | %s |", i+1, name, g.Price, g.ReportedEV(), 100*g.ProfitChance(), remaining)
+		for _, c := range opts.Columns {
+			fmt.Fprintf(&b, " %.4f |", c.Eval(g))
+		}
+		b.WriteString("\n")
+	}
+	return os.WriteFile(filename, []byte(b.String()), 0o644)
+}
+
+// markdownEscape keeps a cell from breaking the table.
+func markdownEscape(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
+
+// WritePerGameMarkdown writes one Markdown page per game into dir for a
+// static site generator such as Hugo or Jekyll: YAML front matter with the
+// game's metrics, then its prize tiers as a table. weight keeps the order
+// given, so a section listing pages by weight follows the -sort ranking.
+func WritePerGameMarkdown(games []model.Game, dir string, opts Options) error {
+	opts = opts.ranked(games)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for i, g := range games {
+		name := strings.TrimSuffix(gameFileName(g), ".json") + ".md"
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(gamePage(g, i+1, opts)), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gamePage renders one game's page for WritePerGameMarkdown.
+func gamePage(g model.Game, weight int, opts Options) string {
+	var b strings.Builder
+	field := func(key string, v any) {
+		// JSON scalars are valid YAML, and quoting strings this way keeps
+		// names with colons or quotes intact.
+		data, _ := json.Marshal(v)
+		fmt.Fprintf(&b, "%s: %s\n", key, data)
+	}
+	b.WriteString("---\n")
+	field("title", g.Name)
+	field("weight", weight)
+	if !g.LastUpdated.IsZero() {
+		field("date", g.LastUpdated.Format(time.RFC3339))
+	}
+	field("game_number", g.GameNumber)
+	field("price", g.Price)
+	field("launch_date", g.LaunchDate)
+	field("source_url", g.URL)
+	field("ev", model.Round(g.ReportedEV(), 2))
+	field("ev_sign", g.EVSign().Name())
+	field("return_per_ticket", model.Round(g.ReturnRate(), 4))
+	field("profit_chance", model.Round(g.ProfitChance(), 4))
+	field("top_prizes_left", g.TopTier().RemainingCount)
+	field("ev_without_top_prize", model.Round(g.EVSign().Apply(g.EVWithoutTopPrize()), 2))
+	field("payout_remaining", model.Round(g.PayoutRemaining(), 4))
+	field("total_original_prizes", g.TotalOriginalPrizes)
+	field("total_remaining_prizes", g.TotalRemainingPrizes)
+	field("remaining_prize_money", g.RemainingPrizeMoney())
+	field("estimated_remaining_tickets", g.RemainingTickets())
+	field("price_percentile", model.Round(opts.Peers.Percentile(g), 0))
+	if date := opts.sellOut(g); date != "" {
+		field("projected_sell_out", date)
+	}
+	field("new", g.New)
+	if date := ended(g); date != "" {
+		field("ended", date)
+	}
+	field("dead", g.Dead())
+	if computed := opts.computed(g); computed != nil {
+		field("computed", computed)
+	}
+	if opts.RunID != "" {
+		field("run_id", opts.RunID)
+	}
+	b.WriteString("---\n\n")
+
+	b.WriteString("| Prize | Original | Remaining | Original Odds | Current Odds |\n")
+	b.WriteString("| ---: | ---: | ---: | ---: | ---: |\n")
+	for _, p := range g.PrizeTiers {
+		orig, cur := g.TierOdds(p)
+		prize := fmt.Sprintf("$%d", p.Value)
+		if p.Tag != "" {
+			prize += " " + markdownEscape(p.Tag)
+		}
+		fmt.Fprintf(&b, "| %s | %d | %d | %s | %s |\n", prize, p.OriginalCount, p.RemainingCount, oneIn(orig), oneIn(cur))
+	}
+	return b.String()
+}
+
+// oneIn formats "1 in N" odds, or a dash when a tier has none left.
+func oneIn(odds float64) string {
+	if odds <= 0 {
+		return "-"
+	}
+	return fmt.Sprintf("1 in %.2f", odds)
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+
+	"msLotto/model"
+)
+
+// Output is one destination a run writes its games to.
+type Output struct {
+	Name  string
+	Write func(games []model.Game) error
+}
+
+// OutputStatus is how one output fared.
+type OutputStatus struct {
+	Name string
+	Err  error
+}
+
+// WriteOutputs runs every output in order, carrying on past failures so one
+// broken writer doesn't cost the others their files.
+func WriteOutputs(games []model.Game, outputs []Output) []OutputStatus {
+	statuses := make([]OutputStatus, len(outputs))
+	for i, o := range outputs {
+		statuses[i] = OutputStatus{Name: o.Name, Err: o.Write(games)}
+	}
+	return statuses
+}
+
+// OutputError lists the outputs that failed.
+type OutputError struct {
+	Statuses []OutputStatus
+}
+
+// CheckOutputs returns an *OutputError if any output failed, else nil.
+func CheckOutputs(statuses []OutputStatus) error {
+	for _, s := range statuses {
+		if s.Err != nil {
+			return &OutputError{Statuses: statuses}
+		}
+	}
+	return nil
+}
+
+func (e *OutputError) Error() string {
+	var failed []string
+	for _, s := range e.Statuses {
+		if s.Err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", s.Name, s.Err))
+		}
+	}
+	return "writing " + strings.Join(failed, "; ")
+}
+
+// Partial reports whether at least one output was still written.
+func (e *OutputError) Partial() bool {
+	for _, s := range e.Statuses {
+		if s.Err == nil {
+			return true
+		}
+	}
+	return false
+}
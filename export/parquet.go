@@ -0,0 +1,149 @@
+package export
+
+import (
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"msLotto/model"
+)
+
+// parquetGame is the games file's schema. Columns are only ever added, at
+// the end, so queries written against an older file keep working.
+type parquetGame struct {
+	GameNumber                int64      `parquet:"game_number"`
+	Name                      string     `parquet:"name"`
+	Price                     int64      `parquet:"price"`
+	Odds                      float64    `parquet:"odds"`
+	LaunchDate                string     `parquet:"launch_date"`
+	TotalOriginalPrizes       int64      `parquet:"total_original_prizes"`
+	TotalRemainingPrizes      int64      `parquet:"total_remaining_prizes"`
+	TotalTickets              int64      `parquet:"total_tickets"`
+	EstimatedOriginalTickets  int64      `parquet:"estimated_original_tickets"`
+	EstimatedRemainingTickets int64      `parquet:"estimated_remaining_tickets"`
+	OriginalPrizeMoney        int64      `parquet:"original_prize_money"`
+	RemainingPrizeMoney       int64      `parquet:"remaining_prize_money"`
+	PayoutRemaining           float64    `parquet:"payout_remaining"`
+	EV                        float64    `parquet:"ev"`
+	ReturnRate                float64    `parquet:"return_per_ticket"`
+	AnnualizedReturn          float64    `parquet:"annualized_return"`
+	PricePercentile           float64    `parquet:"price_percentile"`
+	UPC                       string     `parquet:"upc"`
+	LastUpdated               *time.Time `parquet:"last_updated,optional"`
+	URL                       string     `parquet:"url"`
+	EVSign                    string     `parquet:"ev_sign"`
+	PackSize                  int64      `parquet:"pack_size"`
+	ClaimsPerDay              *float64   `parquet:"claims_per_day,optional"`
+	ProjectedSellOut          *time.Time `parquet:"projected_sell_out,optional"`
+	New                       bool       `parquet:"new"`
+	Ended                     *time.Time `parquet:"ended,optional"`
+	RunID                     string     `parquet:"run_id"`
+	ProfitChance              float64    `parquet:"profit_chance"`
+	TopPrizesLeft             int64      `parquet:"top_prizes_left"`
+	EVWithoutTopPrize         float64    `parquet:"ev_without_top_prize"`
+	ScrapedAt                 *time.Time `parquet:"scraped_at,optional"` // set by WriteHistoryParquet
+
+	// Computed holds the -column values by name.
+	Computed map[string]float64 `parquet:"computed,optional"`
+}
+
+// parquetTier is the prize tiers file's schema, joined to games on
+// game_number and url, and scraped_at in a history file.
+type parquetTier struct {
+	GameNumber     int64      `parquet:"game_number"`
+	URL            string     `parquet:"url"`
+	Position       int32      `parquet:"position"` // order on the page
+	Value          int64      `parquet:"value"`
+	Tag            string     `parquet:"tag"`
+	OriginalCount  int64      `parquet:"original_count"`
+	RemainingCount int64      `parquet:"remaining_count"`
+	Odds           float64    `parquet:"odds"`
+	OriginalOdds   float64    `parquet:"original_odds"`
+	CurrentOdds    float64    `parquet:"current_odds"`
+	ScrapedAt      *time.Time `parquet:"scraped_at,optional"`
+}
+
+// TiersFile is where WriteParquet puts the prize tiers for a games file.
+func TiersFile(filename string) string {
+	return strings.TrimSuffix(filename, ".parquet") + "_tiers.parquet"
+}
+
+// WriteParquet writes the games to filename and their prize tiers to
+// TiersFile(filename).
+func WriteParquet(games []model.Game, filename string, opts Options) error {
+	rows, tiers := opts.ranked(games).parquetRows(games)
+	if err := parquet.WriteFile(filename, rows); err != nil {
+		return err
+	}
+	return parquet.WriteFile(TiersFile(filename), tiers)
+}
+
+// parquetRows converts the games and their prize tiers to the Parquet
+// schemas.
+func (o Options) parquetRows(games []model.Game) ([]parquetGame, []parquetTier) {
+	rows := make([]parquetGame, len(games))
+	var tiers []parquetTier
+	for i, g := range games {
+		rows[i] = parquetGame{
+			GameNumber:                int64(g.GameNumber),
+			Name:                      g.Name,
+			Price:                     int64(g.Price),
+			Odds:                      g.Odds,
+			LaunchDate:                g.LaunchDate,
+			TotalOriginalPrizes:       int64(g.TotalOriginalPrizes),
+			TotalRemainingPrizes:      int64(g.TotalRemainingPrizes),
+			TotalTickets:              int64(g.TotalTickets),
+			EstimatedOriginalTickets:  int64(g.OriginalTickets()),
+			EstimatedRemainingTickets: int64(g.RemainingTickets()),
+			OriginalPrizeMoney:        int64(g.OriginalPrizeMoney()),
+			RemainingPrizeMoney:       int64(g.RemainingPrizeMoney()),
+			PayoutRemaining:           model.Round(g.PayoutRemaining(), 4),
+			EV:                        model.Round(g.ReportedEV(), 2),
+			ReturnRate:                model.Round(g.ReturnRate(), 4),
+			AnnualizedReturn:          model.Round(g.AnnualizedReturn(), 4),
+			PricePercentile:           model.Round(o.Peers.Percentile(g), 0),
+			UPC:                       g.UPC,
+			URL:                       g.URL,
+			EVSign:                    g.EVSign().Name(),
+			PackSize:                  int64(g.PackSize),
+			New:                       g.New,
+			RunID:                     o.RunID,
+			ProfitChance:              model.Round(g.ProfitChance(), 4),
+			TopPrizesLeft:             int64(g.TopTier().RemainingCount),
+			EVWithoutTopPrize:         model.Round(g.EVSign().Apply(g.EVWithoutTopPrize()), 2),
+			Computed:                  o.computed(g),
+		}
+		if !g.LastUpdated.IsZero() {
+			t := g.LastUpdated
+			rows[i].LastUpdated = &t
+		}
+		if !g.Ended.IsZero() {
+			t := g.Ended
+			rows[i].Ended = &t
+		}
+		if rate, ok := o.Claims.ClaimsPerDay(g); ok {
+			rate = model.Round(rate, 1)
+			rows[i].ClaimsPerDay = &rate
+		}
+		if date, ok := o.Claims.SellOutDate(g); ok {
+			rows[i].ProjectedSellOut = &date
+		}
+		for j, p := range g.PrizeTiers {
+			orig, cur := g.TierOdds(p)
+			tiers = append(tiers, parquetTier{
+				GameNumber:     int64(g.GameNumber),
+				URL:            g.URL,
+				Position:       int32(j),
+				Value:          int64(p.Value),
+				Tag:            p.Tag,
+				OriginalCount:  int64(p.OriginalCount),
+				RemainingCount: int64(p.RemainingCount),
+				Odds:           p.Odds,
+				OriginalOdds:   model.Round(orig, 2),
+				CurrentOdds:    model.Round(cur, 2),
+			})
+		}
+	}
+	return rows, tiers
+}
@@ -0,0 +1,93 @@
+package export
+
+import (
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"msLotto/model"
+)
+
+// secretOption matches key=value options that carry credentials, such as the
+// token and user in -notify specs.
+var secretOption = regexp.MustCompile(`(?i)\b(token|user|password|secret|key)=[^,\s&]*`)
+
+var bearerToken = regexp.MustCompile(`(?i)\bbearer\s+\S+`)
+
+// ScrubSecrets replaces credentials in s. It is applied to diagnostics
+// whether or not -redact is set.
+func ScrubSecrets(s string) string {
+	s = secretOption.ReplaceAllString(s, "$1=REDACTED")
+	return bearerToken.ReplaceAllString(s, "Bearer REDACTED")
+}
+
+var urlPattern = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]+`)
+
+// Redactor strips deployment details from outputs meant for publishing:
+// credentials, URLs on hosts other than the public site, and local paths.
+// A nil Redactor only scrubs credentials.
+type Redactor struct {
+	PublicHosts []string          // URLs on these hosts are kept, less any credentials
+	Paths       map[string]string // local path prefix -> what to show instead
+}
+
+// NewRedactor keeps URLs on the site publicURL is on and hides the working
+// directory, home directory and temp directory.
+func NewRedactor(publicURL string) *Redactor {
+	r := &Redactor{Paths: map[string]string{}}
+	if u, err := url.Parse(publicURL); err == nil && u.Hostname() != "" {
+		host := strings.TrimPrefix(u.Hostname(), "www.")
+		r.PublicHosts = []string{host, "www." + host}
+	}
+	if wd, err := os.Getwd(); err == nil {
+		r.Paths[wd] = "."
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		r.Paths[home] = "~"
+	}
+	r.Paths[filepath.Clean(os.TempDir())] = "$TMPDIR"
+	return r
+}
+
+// String redacts s.
+func (r *Redactor) String(s string) string {
+	if r == nil {
+		return ScrubSecrets(s)
+	}
+	s = urlPattern.ReplaceAllStringFunc(s, r.url)
+	// Longest prefix first, so the working directory wins over a home
+	// directory it sits in.
+	prefixes := make([]string, 0, len(r.Paths))
+	for p := range r.Paths {
+		if p != "" && p != "/" {
+			prefixes = append(prefixes, p)
+		}
+	}
+	slices.SortFunc(prefixes, func(a, b string) int { return len(b) - len(a) })
+	for _, p := range prefixes {
+		s = strings.ReplaceAll(s, p, r.Paths[p])
+	}
+	return ScrubSecrets(s)
+}
+
+func (r *Redactor) url(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || !slices.Contains(r.PublicHosts, strings.ToLower(u.Hostname())) {
+		return "[internal URL]"
+	}
+	u.User = nil
+	return u.String()
+}
+
+// Games returns a copy of games with their URLs redacted.
+func (r *Redactor) Games(games []model.Game) []model.Game {
+	out := make([]model.Game, len(games))
+	for i, g := range games {
+		g.URL = r.String(g.URL)
+		out[i] = g
+	}
+	return out
+}
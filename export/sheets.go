@@ -0,0 +1,211 @@
+package export
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"msLotto/model"
+)
+
+// SheetsAppender appends rows to a Google Sheet as a service account. The
+// account's email has to be shared on the spreadsheet as an editor.
+type SheetsAppender struct {
+	SpreadsheetID string
+	Range         string // sheet (tab) name or A1 range to append after, e.g. "Runs"
+	Client        *http.Client
+
+	email    string
+	key      *rsa.PrivateKey
+	tokenURL string
+}
+
+// serviceAccount is the part of a service account key file we use.
+type serviceAccount struct {
+	ClientEmail string `json:"client_email"`
+	PrivateKey  string `json:"private_key"`
+	TokenURI    string `json:"token_uri"`
+}
+
+const sheetsScope = "https://www.googleapis.com/auth/spreadsheets"
+
+// sheetsTimeout bounds each call to Google, token exchange included, so an
+// unresponsive API fails the output instead of hanging the run.
+const sheetsTimeout = 30 * time.Second
+
+// NewSheetsAppender reads the service account key file downloaded from the
+// Google Cloud console.
+func NewSheetsAppender(credentials, spreadsheetID, rng string) (*SheetsAppender, error) {
+	data, err := os.ReadFile(credentials)
+	if err != nil {
+		return nil, err
+	}
+	var sa serviceAccount
+	if err := json.Unmarshal(data, &sa); err != nil {
+		return nil, fmt.Errorf("%s: %w", credentials, err)
+	}
+	block, _ := pem.Decode([]byte(sa.PrivateKey))
+	if sa.ClientEmail == "" || block == nil {
+		return nil, fmt.Errorf("%s: not a service account key file", credentials)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", credentials, err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: private key is not RSA", credentials)
+	}
+	if sa.TokenURI == "" {
+		sa.TokenURI = "https://oauth2.googleapis.com/token"
+	}
+	return &SheetsAppender{
+		SpreadsheetID: spreadsheetID,
+		Range:         rng,
+		Client:        &http.Client{Timeout: sheetsTimeout},
+		email:         sa.ClientEmail,
+		key:           key,
+		tokenURL:      sa.TokenURI,
+	}, nil
+}
+
+// accessToken trades a signed JWT for an OAuth access token, the service
+// account flow Google's client libraries use.
+func (s *SheetsAppender) accessToken() (string, error) {
+	enc := base64.RawURLEncoding
+	now := time.Now()
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.email,
+		"scope": sheetsScope,
+		"aud":   s.tokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(unsigned))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.key, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.Client.PostForm(s.tokenURL, url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {unsigned + "." + enc.EncodeToString(sig)},
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("getting access token: %s: %s", resp.Status, readError(resp.Body))
+	}
+	var tok struct {
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", err
+	}
+	if tok.AccessToken == "" {
+		return "", errors.New("getting access token: empty token")
+	}
+	return tok.AccessToken, nil
+}
+
+// Append adds rows after the last row of the range.
+func (s *SheetsAppender) Append(rows [][]any) error {
+	token, err := s.accessToken()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]any{"values": rows})
+	if err != nil {
+		return err
+	}
+	u := fmt.Sprintf("https://sheets.googleapis.com/v4/spreadsheets/%s/values/%s:append?valueInputOption=USER_ENTERED&insertDataOption=INSERT_ROWS",
+		url.PathEscape(s.SpreadsheetID), url.PathEscape(s.Range))
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("appending to sheet: %s: %s", resp.Status, readError(resp.Body))
+	}
+	return nil
+}
+
+// readError pulls the message out of a Google API error body.
+func readError(r io.Reader) string {
+	data, _ := io.ReadAll(io.LimitReader(r, 4096))
+	var e struct {
+		Error struct {
+			Message string `json:"message"`
+		} `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if json.Unmarshal(data, &e) == nil {
+		if e.Error.Message != "" {
+			return e.Error.Message
+		}
+		if e.Description != "" {
+			return e.Description
+		}
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// SheetRows is one row per game for a run started at started, so a sheet
+// appended to every run keeps each game's history. The columns are run time,
+// game number, name, price, odds, remaining prizes, remaining prize money,
+// payout remaining, EV, return per ticket, last updated, URL, profit chance,
+// the run ID from opts and then opts.Columns.
+func SheetRows(started time.Time, games []model.Game, opts Options) [][]any {
+	rows := make([][]any, len(games))
+	at := started.Format("2006-01-02 15:04:05")
+	for i, g := range games {
+		rows[i] = []any{
+			at,
+			g.GameNumber,
+			g.Name,
+			g.Price,
+			model.Round(g.Odds, 2),
+			g.TotalRemainingPrizes,
+			g.RemainingPrizeMoney(),
+			model.Round(g.PayoutRemaining(), 4),
+			model.Round(g.ReportedEV(), 2),
+			model.Round(g.ReturnRate(), 4),
+			lastUpdated(g),
+			g.URL,
+			model.Round(g.ProfitChance(), 4),
+			opts.RunID,
+		}
+		for _, c := range opts.Columns {
+			rows[i] = append(rows[i], model.Round(c.Eval(g), 4))
+		}
+	}
+	return rows
+}
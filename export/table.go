@@ -0,0 +1,91 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"msLotto/analyze"
+	"msLotto/model"
+)
+
+// WriteTable prints the games, in the order given, as an aligned table for
+// reading in a terminal. Dead games follow in a section of their own.
+func WriteTable(w io.Writer, games []model.Game, opts Options) error {
+	live, dead := analyze.SplitDead(games)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	opts.writeTableHeader(tw, evSign(games))
+	for i, g := range live {
+		opts.writeTableRow(tw, i+1, g)
+	}
+	opts.writeDeadSection(tw, dead, len(live) > 0)
+	return tw.Flush()
+}
+
+// WriteGroupedTable prints one titled table per ticket price, cheapest first,
+// like WriteGroupedCSV, and then the dead games.
+func WriteGroupedTable(w io.Writer, games []model.Game, opts Options) error {
+	live, dead := analyze.SplitDead(games)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for i, group := range analyze.GroupByPrice(live) {
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "$%d Tickets\n", group[0].Price)
+		opts.writeTableHeader(tw, evSign(group))
+		for j, g := range group {
+			opts.writeTableRow(tw, j+1, g)
+		}
+	}
+	opts.writeDeadSection(tw, dead, len(live) > 0)
+	return tw.Flush()
+}
+
+// writeDeadSection lists the games with no prizes left, which can't be
+// ranked on EV, under a title of their own.
+func (o Options) writeDeadSection(w io.Writer, dead []model.Game, after bool) {
+	if len(dead) == 0 {
+		return
+	}
+	if after {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "Effectively dead (no prizes left)")
+	o.writeTableHeader(w, evSign(dead))
+	for i, g := range dead {
+		o.writeTableRow(w, i+1, g)
+	}
+}
+
+func (o Options) writeTableHeader(w io.Writer, sign model.EVSign) {
+	fmt.Fprintf(w, "#\tName\tPrice\tOdds\t%s\tReturn\tProfit Chance\tTop Prize\tTop Prizes Left\tEV w/o Top\tClaims/Day\tSells Out", sign.Label())
+	for _, c := range o.Columns {
+		fmt.Fprintf(w, "\t%s", c.Name)
+	}
+	fmt.Fprintln(w)
+}
+
+func (o Options) writeTableRow(w io.Writer, rank int, g model.Game) {
+	top := g.TopTier()
+	prize, left := "n/a", "n/a"
+	if top.OriginalCount > 0 {
+		prize = fmt.Sprintf("$%d", top.Value)
+		if top.Tag != "" {
+			prize += " " + top.Tag
+		}
+		left = fmt.Sprintf("%d of %d", top.RemainingCount, top.OriginalCount)
+	}
+	name := g.Name
+	switch {
+	case g.New:
+		name += " (new)"
+	case !g.Ended.IsZero():
+		name += " (ended " + ended(g) + ")"
+	}
+	fmt.Fprintf(w, "%d\t%s\t$%d\t1:%.2f\t%.2f\t%+.1f%%\t%.2f%%\t%s\t%s\t%.2f\t%s\t%s", rank, name, g.Price, g.Odds, g.ReportedEV(), 100*g.ReturnRate(), 100*g.ProfitChance(), prize, left,
+		g.EVSign().Apply(g.EVWithoutTopPrize()), o.claimsPerDay(g), o.sellOut(g))
+	for _, c := range o.Columns {
+		fmt.Fprintf(w, "\t%.4f", c.Eval(g))
+	}
+	fmt.Fprintln(w)
+}
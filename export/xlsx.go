@@ -0,0 +1,172 @@
+package export
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"msLotto/model"
+)
+
+const summarySheet = "Summary"
+
+var tierHeader = []string{"Prize", "Tag", "Odds", "Original Count", "Remaining Count", "Original Odds", "Current Odds"}
+
+// xlsxRow is csvRow with numbers left as numbers, so they sort and sum in a
+// spreadsheet.
+func (o Options) xlsxRow(g model.Game) []any {
+	row := []any{
+		g.Name,
+		g.Price,
+		model.Round(g.Odds, 2),
+		g.LaunchDate,
+		g.TotalOriginalPrizes,
+		g.TotalRemainingPrizes,
+		g.OriginalTickets(),
+		g.RemainingTickets(),
+		g.OriginalPrizeMoney(),
+		g.RemainingPrizeMoney(),
+		model.Round(g.PayoutRemaining(), 4),
+		model.Round(g.ReportedEV(), 2),
+		model.Round(g.ReturnRate(), 4),
+		model.Round(g.ProfitChance(), 4),
+		g.TopTier().RemainingCount,
+		model.Round(g.EVSign().Apply(g.EVWithoutTopPrize()), 2),
+		model.Round(g.AnnualizedReturn(), 4),
+		g.BreakEvenLowPrizes(),
+		g.BreakEvenTopPrizes(),
+		model.Round(o.Peers.Percentile(g), 0),
+		o.claimsPerDayValue(g),
+		o.sellOut(g),
+		newFlag(g),
+		ended(g),
+		lastUpdated(g),
+		g.URL,
+	}
+	for _, c := range o.Columns {
+		row = append(row, model.Round(c.Eval(g), 4))
+	}
+	return row
+}
+
+// claimsPerDayValue is the claim rate as a number, or a blank cell.
+func (o Options) claimsPerDayValue(g model.Game) any {
+	if rate, ok := o.Claims.ClaimsPerDay(g); ok {
+		return model.Round(rate, 1)
+	}
+	return ""
+}
+
+// sheetName is a game's sheet: its number and name, cut to Excel's 31
+// characters and without the characters Excel doesn't allow. used keeps the
+// names unique.
+func sheetName(g model.Game, used map[string]bool) string {
+	name := g.Name
+	if g.GameNumber != 0 {
+		name = fmt.Sprintf("%d %s", g.GameNumber, g.Name)
+	}
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '-'
+		}
+		return r
+	}, name)
+	base := []rune(name)
+	if len(base) > 31 {
+		base = base[:31]
+	}
+	name = string(base)
+	for i := 2; used[strings.ToLower(name)] || name == ""; i++ {
+		suffix := fmt.Sprintf(" (%d)", i)
+		name = string(base[:min(len(base), 31-len(suffix))]) + suffix
+	}
+	used[strings.ToLower(name)] = true
+	return name
+}
+
+// WriteXLSX writes a workbook with a summary sheet of every game, in the
+// order given, followed by one sheet per game with its prize tiers. Header
+// rows are frozen and money columns are formatted as currency.
+func WriteXLSX(games []model.Game, filename string, opts Options) error {
+	opts = opts.ranked(games)
+	f := excelize.NewFile()
+	defer f.Close()
+
+	currencyFmt := "$#,##0.00"
+	currency, err := f.NewStyle(&excelize.Style{CustomNumFmt: &currencyFmt})
+	if err != nil {
+		return err
+	}
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	if err := f.SetSheetName("Sheet1", summarySheet); err != nil {
+		return err
+	}
+	rows := [][]any{toAny(opts.csvHeaderRow(evSign(games)))}
+	for _, g := range games {
+		rows = append(rows, opts.xlsxRow(g))
+	}
+	evCol := slices.Index(csvHeader, "EV") + 1
+	if err := writeSheet(f, summarySheet, rows, bold, currency, evCol); err != nil {
+		return err
+	}
+
+	used := map[string]bool{strings.ToLower(summarySheet): true}
+	for _, g := range games {
+		sheet := sheetName(g, used)
+		if _, err := f.NewSheet(sheet); err != nil {
+			return err
+		}
+		rows := [][]any{toAny(tierHeader)}
+		for _, p := range g.PrizeTiers {
+			orig, cur := g.TierOdds(p)
+			rows = append(rows, []any{p.Value, p.Tag, p.Odds, p.OriginalCount, p.RemainingCount, model.Round(orig, 2), model.Round(cur, 2)})
+		}
+		if err := writeSheet(f, sheet, rows, bold, currency, 1); err != nil {
+			return err
+		}
+	}
+	return f.SaveAs(filename)
+}
+
+// writeSheet fills sheet from rows, the first being the header, freezes the
+// header and formats column currencyCol (1-based) as currency.
+func writeSheet(f *excelize.File, sheet string, rows [][]any, bold, currency, currencyCol int) error {
+	for i, row := range rows {
+		cell, err := excelize.CoordinatesToCellName(1, i+1)
+		if err != nil {
+			return err
+		}
+		if err := f.SetSheetRow(sheet, cell, &row); err != nil {
+			return err
+		}
+	}
+	last, err := excelize.CoordinatesToCellName(len(rows[0]), 1)
+	if err != nil {
+		return err
+	}
+	if err := f.SetCellStyle(sheet, "A1", last, bold); err != nil {
+		return err
+	}
+	if len(rows) > 1 {
+		top, _ := excelize.CoordinatesToCellName(currencyCol, 2)
+		bottom, _ := excelize.CoordinatesToCellName(currencyCol, len(rows))
+		if err := f.SetCellStyle(sheet, top, bottom, currency); err != nil {
+			return err
+		}
+	}
+	return f.SetPanes(sheet, &excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"})
+}
+
+func toAny(row []string) []any {
+	out := make([]any, len(row))
+	for i, v := range row {
+		out[i] = v
+	}
+	return out
+}
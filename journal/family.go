@@ -0,0 +1,129 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// generationSuffixes are trailing words a reissue adds to a game's name,
+// which FamilyKey drops so the reissue joins the original's family.
+var generationSuffixes = map[string]bool{
+	"ii": true, "iii": true, "iv": true, "v": true, "vi": true,
+	"edition": true, "series": true,
+}
+
+// FamilyKey normalizes a game name so that reissues of the same game share
+// a key: case, punctuation and a trailing generation marker such as a year,
+// an edition number or a roman numeral are ignored. "100X The Money" and
+// "100X the Money II" both become "100x the money".
+func FamilyKey(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 {
+		w := words[len(words)-1]
+		if !generationSuffixes[w] && !isGenerationNumber(w) {
+			break
+		}
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+// isGenerationNumber matches a year or an ordinal ("2025", "2nd"). Other
+// bare numbers are left alone, as in "Lucky 7".
+func isGenerationNumber(w string) bool {
+	digits := strings.TrimRightFunc(w, unicode.IsLetter)
+	if digits == "" || strings.TrimFunc(digits, unicode.IsDigit) != "" {
+		return false
+	}
+	switch w[len(digits):] {
+	case "":
+		return len(digits) == 4 && (digits[:2] == "19" || digits[:2] == "20")
+	case "st", "nd", "rd", "th":
+		return true
+	}
+	return false
+}
+
+// Generation is one game number within a family, as seen in the journal.
+type Generation struct {
+	GameNumber int
+	Name       string
+	Price      int
+	First      time.Time // first snapshot it appeared in
+	Last       time.Time
+	FirstEV    float64
+	LastEV     float64
+	Snapshots  int
+}
+
+// Family is a game and its reissues, oldest generation first.
+type Family struct {
+	Key         string
+	Generations []Generation
+}
+
+// Families links games across the journal by FamilyKey and returns the ones
+// that have been issued under more than one game number, by key.
+func Families(snaps []Snapshot) []Family {
+	byKey := map[string]map[int]*Generation{}
+	for _, s := range snaps {
+		for _, e := range s.Index {
+			key := FamilyKey(e.Name)
+			gens, ok := byKey[key]
+			if !ok {
+				gens = map[int]*Generation{}
+				byKey[key] = gens
+			}
+			g, ok := gens[e.GameNumber]
+			if !ok {
+				g = &Generation{GameNumber: e.GameNumber, Name: e.Name, Price: e.Price, First: s.Time, FirstEV: e.EV}
+				gens[e.GameNumber] = g
+			}
+			g.Last, g.LastEV = s.Time, e.EV
+			g.Snapshots++
+		}
+	}
+
+	var out []Family
+	for key, gens := range byKey {
+		if len(gens) < 2 {
+			continue
+		}
+		f := Family{Key: key}
+		for _, g := range gens {
+			f.Generations = append(f.Generations, *g)
+		}
+		sort.Slice(f.Generations, func(i, j int) bool {
+			a, b := f.Generations[i], f.Generations[j]
+			if !a.First.Equal(b.First) {
+				return a.First.Before(b.First)
+			}
+			return a.GameNumber < b.GameNumber
+		})
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+// WriteFamilies prints each family's generations with their EV at first
+// and last sight, so a reissue can be compared with the game it replaced.
+func WriteFamilies(w io.Writer, fams []Family) {
+	if len(fams) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Game families across reissues:")
+	for _, f := range fams {
+		fmt.Fprintf(w, "  %s\n", f.Generations[len(f.Generations)-1].Name)
+		for _, g := range f.Generations {
+			fmt.Fprintf(w, "    #%-6d $%-3d %s to %s  EV %6.2f to %6.2f over %d snapshots\n",
+				g.GameNumber, g.Price, g.First.Format("2006-01-02"), g.Last.Format("2006-01-02"), g.FirstEV, g.LastEV, g.Snapshots)
+		}
+	}
+}
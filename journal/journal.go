@@ -0,0 +1,90 @@
+// Package journal keeps scrape snapshots in a git repository and reads them
+// back for historical views.
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CommitSnapshot commits everything under dir to the git repository there,
+// creating the repository on first use. Runs that change nothing are skipped
+// so the log only grows when the data does.
+func CommitSnapshot(dir string, now time.Time) error {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); os.IsNotExist(err) {
+		if err := runGit(dir, "init", "-q"); err != nil {
+			return err
+		}
+	}
+	if err := runGit(dir, "add", "-A"); err != nil {
+		return err
+	}
+	if err := runGit(dir, "diff", "--cached", "--quiet"); err == nil {
+		return nil
+	}
+	msg := "mslotto snapshot " + now.UTC().Format(time.RFC3339)
+	return runGit(dir, "commit", "-q", "-m", msg)
+}
+
+func runGit(dir string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// IndexEntry is one game's line in a snapshot's index.json.
+type IndexEntry struct {
+	GameNumber int     `json:"game_number"`
+	Name       string  `json:"name"`
+	Price      int     `json:"price"`
+	EV         float64 `json:"ev"`
+	File       string  `json:"file"`
+
+	// LastUpdated is the site's own update date, so history can tell a
+	// snapshot of unchanged site data from a real observation.
+	LastUpdated time.Time `json:"last_updated,omitzero"`
+}
+
+// Snapshot is one journaled run: when it was committed and the index it wrote.
+type Snapshot struct {
+	Time  time.Time
+	Index []IndexEntry
+}
+
+// ReadJournal loads every committed index.json in dir, oldest first.
+func ReadJournal(dir string) ([]Snapshot, error) {
+	out, err := exec.Command("git", "-C", dir, "log", "--reverse", "--format=%H %cI", "--", "index.json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git log in %s: %w", dir, err)
+	}
+
+	var snaps []Snapshot
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line == "" {
+			continue
+		}
+		hash, when, _ := strings.Cut(line, " ")
+		t, err := time.Parse(time.RFC3339, when)
+		if err != nil {
+			return nil, err
+		}
+		data, err := exec.Command("git", "-C", dir, "show", hash+":index.json").Output()
+		if err != nil {
+			return nil, fmt.Errorf("git show %s: %w", hash, err)
+		}
+		var index []IndexEntry
+		if err := json.Unmarshal(data, &index); err != nil {
+			return nil, fmt.Errorf("index.json at %s: %w", hash, err)
+		}
+		snaps = append(snaps, Snapshot{Time: t, Index: index})
+	}
+	return snaps, nil
+}
@@ -0,0 +1,125 @@
+package journal
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// evSwing is the widest EV range one game has shown across the journal.
+type evSwing struct {
+	Name     string
+	Low      float64
+	High     float64
+	Snapshot int
+}
+
+// WriteStats summarizes what a snapshot journal contains.
+func WriteStats(w io.Writer, snaps []Snapshot, top int) {
+	if len(snaps) == 0 {
+		fmt.Fprintln(w, "No snapshots recorded yet.")
+		return
+	}
+
+	swings := map[string]*evSwing{}
+	for _, s := range snaps {
+		for _, e := range s.Index {
+			sw, ok := swings[e.File]
+			if !ok {
+				swings[e.File] = &evSwing{Name: e.Name, Low: e.EV, High: e.EV, Snapshot: 1}
+				continue
+			}
+			sw.Low = min(sw.Low, e.EV)
+			sw.High = max(sw.High, e.EV)
+			sw.Snapshot++
+		}
+	}
+
+	list := make([]evSwing, 0, len(swings))
+	for _, sw := range swings {
+		list = append(list, *sw)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i].High-list[i].Low, list[j].High-list[j].Low
+		if a != b {
+			return a > b
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > top {
+		list = list[:top]
+	}
+
+	first, last := snaps[0].Time, snaps[len(snaps)-1].Time
+	fmt.Fprintf(w, "Snapshots:     %d\n", len(snaps))
+	fmt.Fprintf(w, "Date range:    %s to %s\n", first.Format("2006-01-02 15:04"), last.Format("2006-01-02 15:04"))
+	fmt.Fprintf(w, "Games tracked: %d\n", len(swings))
+	fmt.Fprintln(w, "Biggest EV swings:")
+	for _, sw := range list {
+		fmt.Fprintf(w, "  %-30s %6.2f to %6.2f (%.2f) over %d snapshots\n", sw.Name, sw.Low, sw.High, sw.High-sw.Low, sw.Snapshot)
+	}
+}
+
+// RankStability is how a game's EV rank (1 = smallest expected loss) has
+// moved over the snapshots it appeared in.
+type RankStability struct {
+	Name      string
+	MeanRank  float64
+	RankSD    float64 // rank volatility; low means a consistent placing
+	Snapshots int
+}
+
+// RankVolatility ranks every game in each of the last k snapshots and
+// returns per-game rank mean and standard deviation, best mean rank first.
+func RankVolatility(snaps []Snapshot, k int) []RankStability {
+	if len(snaps) > k {
+		snaps = snaps[len(snaps)-k:]
+	}
+	ranks := map[string][]float64{}
+	names := map[string]string{}
+	for _, s := range snaps {
+		entries := append([]IndexEntry(nil), s.Index...)
+		sort.SliceStable(entries, func(i, j int) bool { return entries[i].EV < entries[j].EV })
+		for i, e := range entries {
+			ranks[e.File] = append(ranks[e.File], float64(i+1))
+			names[e.File] = e.Name
+		}
+	}
+
+	out := make([]RankStability, 0, len(ranks))
+	for file, rs := range ranks {
+		var sum, sq float64
+		for _, r := range rs {
+			sum += r
+		}
+		mean := sum / float64(len(rs))
+		for _, r := range rs {
+			sq += (r - mean) * (r - mean)
+		}
+		out = append(out, RankStability{
+			Name:      names[file],
+			MeanRank:  mean,
+			RankSD:    math.Sqrt(sq / float64(len(rs))),
+			Snapshots: len(rs),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].MeanRank != out[j].MeanRank {
+			return out[i].MeanRank < out[j].MeanRank
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// WriteRankStability prints the top games by mean rank with their volatility.
+func WriteRankStability(w io.Writer, rs []RankStability, top int) {
+	if len(rs) > top {
+		rs = rs[:top]
+	}
+	fmt.Fprintln(w, "EV rank stability (1 = best):")
+	for _, r := range rs {
+		fmt.Fprintf(w, "  %-30s mean rank %5.1f  volatility %4.1f over %d snapshots\n", r.Name, r.MeanRank, r.RankSD, r.Snapshots)
+	}
+}
@@ -0,0 +1,116 @@
+package model
+
+import (
+	"fmt"
+	"math"
+)
+
+// Estimate is a ticket count with the range the estimating model can defend.
+type Estimate struct {
+	Value int
+	Low   int
+	High  int
+}
+
+// Estimator turns what a game page publishes into ticket counts. The site
+// never states how many tickets are left, so every model is an estimate.
+type Estimator interface {
+	OriginalTickets(g Game) Estimate
+	RemainingTickets(g Game) Estimate
+}
+
+// OddsModel multiplies the overall odds by the number of winning tickets.
+// Odds are published to two decimals, which is where the bounds come from.
+type OddsModel struct{}
+
+func (OddsModel) OriginalTickets(g Game) Estimate {
+	return oddsEstimate(g.Odds, g.TotalOriginalPrizes)
+}
+
+func (OddsModel) RemainingTickets(g Game) Estimate {
+	return oddsEstimate(g.Odds, g.TotalRemainingPrizes)
+}
+
+func oddsEstimate(odds float64, prizes int) Estimate {
+	if odds == 0 {
+		return Estimate{}
+	}
+	return Estimate{
+		Value: int(math.Round(odds * float64(prizes))),
+		Low:   int(math.Round((odds - 0.005) * float64(prizes))),
+		High:  int(math.Round((odds + 0.005) * float64(prizes))),
+	}
+}
+
+// PrintedCountModel uses the printed ticket count from the game details and
+// assumes winners and losers sell through at the same rate.
+type PrintedCountModel struct{}
+
+func (PrintedCountModel) OriginalTickets(g Game) Estimate {
+	return Estimate{Value: g.TotalTickets, Low: g.TotalTickets, High: g.TotalTickets}
+}
+
+func (PrintedCountModel) RemainingTickets(g Game) Estimate {
+	if g.TotalTickets == 0 || g.TotalOriginalPrizes == 0 {
+		return Estimate{}
+	}
+	claimed := g.TotalOriginalPrizes - g.TotalRemainingPrizes
+	// Every claimed prize is a sold ticket, and every unclaimed prize is at
+	// least one ticket that could still be out there.
+	return Estimate{
+		Value: int(math.Round(float64(g.TotalTickets) * float64(g.TotalRemainingPrizes) / float64(g.TotalOriginalPrizes))),
+		Low:   g.TotalRemainingPrizes,
+		High:  g.TotalTickets - claimed,
+	}
+}
+
+// TierOddsModel estimates the print run from each tier that publishes its own
+// odds and reports the spread between tiers as the bounds.
+type TierOddsModel struct{}
+
+func (TierOddsModel) OriginalTickets(g Game) Estimate {
+	var sum float64
+	var n int
+	low, high := math.MaxInt, 0
+	for _, p := range g.PrizeTiers {
+		if p.Odds == 0 || p.OriginalCount == 0 {
+			continue
+		}
+		t := int(math.Round(p.Odds * float64(p.OriginalCount)))
+		sum += float64(t)
+		n++
+		low = min(low, t)
+		high = max(high, t)
+	}
+	if n == 0 {
+		return Estimate{}
+	}
+	return Estimate{Value: int(math.Round(sum / float64(n))), Low: low, High: high}
+}
+
+func (m TierOddsModel) RemainingTickets(g Game) Estimate {
+	orig := m.OriginalTickets(g)
+	if g.TotalOriginalPrizes == 0 {
+		return Estimate{}
+	}
+	frac := float64(g.TotalRemainingPrizes) / float64(g.TotalOriginalPrizes)
+	return Estimate{
+		Value: int(math.Round(float64(orig.Value) * frac)),
+		Low:   int(math.Round(float64(orig.Low) * frac)),
+		High:  int(math.Round(float64(orig.High) * frac)),
+	}
+}
+
+var estimators = map[string]Estimator{
+	"odds":    OddsModel{},
+	"printed": PrintedCountModel{},
+	"tier":    TierOddsModel{},
+}
+
+func EstimatorByName(name string) (Estimator, error) {
+	e, ok := estimators[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown estimation model %q", name)
+	}
+	return e, nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import "testing"
+
+func TestEstimators(t *testing.T) {
+	// 1000 winners at 1 in 3.5 overall, 400 of them left, from a printed run
+	// of 3600 tickets. The two tiers imply print runs of 3500 and 3600.
+	g := Game{
+		Odds: 3.5,
+		PrizeTiers: []PrizeTier{
+			{Value: 20, OriginalCount: 100, RemainingCount: 40, Odds: 35},
+			{Value: 5, OriginalCount: 900, RemainingCount: 360, Odds: 4},
+			{Value: 2, OriginalCount: 1, RemainingCount: 0},
+		},
+		TotalOriginalPrizes:  1000,
+		TotalRemainingPrizes: 400,
+		TotalTickets:         3600,
+	}
+	tests := []struct {
+		name                string
+		model               Estimator
+		g                   Game
+		original, remaining Estimate
+	}{
+		{"odds", OddsModel{}, g, Estimate{3500, 3495, 3505}, Estimate{1400, 1398, 1402}},
+		{"odds without odds", OddsModel{}, Game{TotalOriginalPrizes: 1000}, Estimate{}, Estimate{}},
+		{"printed", PrintedCountModel{}, g, Estimate{3600, 3600, 3600}, Estimate{1440, 400, 3000}},
+		{"printed without a count", PrintedCountModel{}, Game{TotalOriginalPrizes: 1000, TotalRemainingPrizes: 400}, Estimate{}, Estimate{}},
+		{"tier", TierOddsModel{}, g, Estimate{3550, 3500, 3600}, Estimate{1420, 1400, 1440}},
+		{"tier without tier odds", TierOddsModel{}, Game{PrizeTiers: []PrizeTier{{OriginalCount: 10}}, TotalOriginalPrizes: 10}, Estimate{}, Estimate{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.model.OriginalTickets(tt.g); got != tt.original {
+				t.Errorf("OriginalTickets = %+v, want %+v", got, tt.original)
+			}
+			if got := tt.model.RemainingTickets(tt.g); got != tt.remaining {
+				t.Errorf("RemainingTickets = %+v, want %+v", got, tt.remaining)
+			}
+		})
+	}
+}
+
+func TestEstimatorByName(t *testing.T) {
+	tests := []struct {
+		name string
+		want Estimator
+	}{
+		{"odds", OddsModel{}},
+		{"printed", PrintedCountModel{}},
+		{"tier", TierOddsModel{}},
+	}
+	for _, tt := range tests {
+		if got, err := EstimatorByName(tt.name); err != nil || got != tt.want {
+			t.Errorf("EstimatorByName(%q) = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+	}
+	if _, err := EstimatorByName("guess"); err == nil {
+		t.Error("EstimatorByName accepted an unknown model")
+	}
+}
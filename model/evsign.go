@@ -0,0 +1,57 @@
+package model
+
+import "fmt"
+
+// EVSign is how EV is reported in outputs. The EV math, sorting included,
+// always works in NetLoss terms; only the reported number and its label
+// change.
+type EVSign int
+
+const (
+	// NetLoss is price minus expected winnings: 1.20 means a $5 ticket is
+	// expected to lose $1.20.
+	NetLoss EVSign = iota
+	// ExpectedReturn is expected winnings minus price: the same ticket
+	// reports -1.20, and a positive number is an expected profit.
+	ExpectedReturn
+)
+
+// EVSignByName maps an -ev-sign value to its convention.
+func EVSignByName(name string) (EVSign, error) {
+	switch name {
+	case "loss":
+		return NetLoss, nil
+	case "return":
+		return ExpectedReturn, nil
+	}
+	return 0, fmt.Errorf("unknown EV sign %q: want loss or return", name)
+}
+
+// Label is the EV column heading, spelling out which way the sign goes.
+func (s EVSign) Label() string {
+	if s == ExpectedReturn {
+		return "EV (expected return, + is profit)"
+	}
+	return "EV (net loss, + is loss)"
+}
+
+// Name is the convention's name in JSON and Parquet outputs.
+func (s EVSign) Name() string {
+	if s == ExpectedReturn {
+		return "expected_return"
+	}
+	return "net_loss"
+}
+
+// Apply converts a NetLoss EV to the convention.
+func (s EVSign) Apply(ev float64) float64 {
+	if s == ExpectedReturn {
+		return -ev
+	}
+	return ev
+}
+
+// ReportedEV is EV with the game's EVSign applied.
+func (g *Game) ReportedEV() float64 {
+	return g.EVSign().Apply(g.EV())
+}
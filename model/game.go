@@ -0,0 +1,232 @@
+// Package model holds the scratch-off game types and the expected value math
+// built on them.
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type PrizeTier struct {
+	Value          int     `json:"value"`
+	OriginalCount  int     `json:"original_count"`
+	RemainingCount int     `json:"remaining_count"`
+	Odds           float64 `json:"odds,omitempty"` // per-tier odds when the site lists them, else 0
+	Tag            string  `json:"tag,omitempty"`  // bonus label printed with the prize, e.g. "WIN ALL"
+}
+
+type Game struct {
+	Name                 string      `json:"name"`
+	Price                int         `json:"price"`
+	Odds                 float64     `json:"odds"` // overall odds (“1:4.50” → 4.50)
+	LaunchDate           string      `json:"launch_date"`
+	GameNumber           int         `json:"game_number"`
+	PrizeTiers           []PrizeTier `json:"prize_tiers"`
+	TotalOriginalPrizes  int         `json:"total_original_prizes"`   // sum of all OriginalCount
+	TotalRemainingPrizes int         `json:"total_remaining_prizes"`  // sum of all RemainingCount
+	TotalTickets         int         `json:"total_tickets,omitempty"` // printed ticket count when the page lists it
+	PackSize             int         `json:"pack_size,omitempty"`     // tickets per pack when the page lists it
+	UPC                  string      `json:"upc,omitempty"`           // from the printable game sheet, see -pdf
+	LastUpdated          time.Time   `json:"last_updated,omitzero"`   // when the site last updated the prize counts
+	URL                  string      `json:"url"`
+	New                  bool        `json:"new,omitempty"`            // first seen this run, see pipeline.KnownGames
+	Ended                time.Time   `json:"ended,omitzero"`           // when an archived game left the active list
+	ParserVersion        int         `json:"parser_version,omitempty"` // scrape.ParserVersion that read the page, 0 when not recorded
+	Settings             *Settings   `json:"-"`                        // how its numbers are worked out, nil for the defaults; see ApplySettings
+}
+
+func (g *Game) OriginalTickets() int {
+	return g.Estimator().OriginalTickets(*g).Value
+}
+func (g *Game) RemainingTickets() int {
+	return g.Estimator().RemainingTickets(*g).Value
+}
+func (g *Game) EV() float64 {
+	remainingTickets := g.RemainingTickets()
+	if remainingTickets == 0 {
+		return float64(g.Price) // no remaining tickets, you “lose” your ticket
+	}
+
+	var expectedWin float64
+	for _, p := range g.PrizeTiers {
+		v := g.TierValue(p)
+		if p.RemainingCount <= 0 || v <= 0 {
+			continue
+		}
+		prob := float64(p.RemainingCount) / float64(remainingTickets)
+		expectedWin += prob * v
+	}
+
+	return float64(g.Price) - expectedWin
+}
+
+// ReturnRate is the expected return of one ticket relative to its price, so
+// -0.30 means you expect to get back 70 cents of every dollar spent.
+func (g *Game) ReturnRate() float64 {
+	return ReturnRate(g.Price, g.EV())
+}
+
+// ReturnRate is the expected return of a ticket costing price with expected
+// loss ev, relative to its price.
+func ReturnRate(price int, ev float64) float64 {
+	if price == 0 {
+		return 0
+	}
+	return -ev / float64(price)
+}
+
+// Dead reports whether the game is effectively dead: no prizes left to win,
+// so its EV is the whole price however it ranks. Such games are kept out of
+// recommendations.
+func (g *Game) Dead() bool {
+	return g.TotalRemainingPrizes <= 0
+}
+
+// ProfitChance is the probability a ticket bought now pays strictly more than
+// it cost. Break-even prizes don't count, and neither do tiers worth nothing
+// in cash.
+func (g *Game) ProfitChance() float64 {
+	remainingTickets := g.RemainingTickets()
+	if remainingTickets == 0 {
+		return 0
+	}
+	var winners int
+	for _, p := range g.PrizeTiers {
+		if p.RemainingCount > 0 && g.TierValue(p) > float64(g.Price) {
+			winners += p.RemainingCount
+		}
+	}
+	return float64(winners) / float64(remainingTickets)
+}
+
+// playsPerYear is the cadence used to annualize ReturnRate: one ticket a week,
+// with winnings rolled into the next ticket like an investment would compound.
+const playsPerYear = 52
+
+// AnnualizedReturn compounds ReturnRate over playsPerYear plays.
+func (g *Game) AnnualizedReturn() float64 {
+	return math.Pow(1+g.ReturnRate(), playsPerYear) - 1
+}
+
+// breakEvenShortfall is how many prize dollars the remaining tickets are
+// missing for an average ticket to pay back its price. Zero when the game is
+// already at or above break-even or has no remaining ticket estimate.
+func (g *Game) breakEvenShortfall() float64 {
+	remaining := g.RemainingTickets()
+	ev := g.EV()
+	if remaining == 0 || ev <= 0 {
+		return 0
+	}
+	return ev * float64(remaining)
+}
+
+// BreakEvenLowPrizes is how many extra prizes of the smallest tier would have
+// to be printed onto otherwise losing tickets for the game to break even.
+func (g *Game) BreakEvenLowPrizes() int {
+	var low float64
+	for _, p := range g.PrizeTiers {
+		if v := g.TierValue(p); v > 0 && (low == 0 || v < low) {
+			low = v
+		}
+	}
+	if low == 0 {
+		return 0
+	}
+	return int(math.Ceil(g.breakEvenShortfall() / low))
+}
+
+// TopTier is the tier with the largest cash value, or the zero tier when the
+// game has no prizes.
+func (g *Game) TopTier() PrizeTier {
+	var top PrizeTier
+	for _, p := range g.PrizeTiers {
+		if g.TierValue(p) > g.TierValue(top) {
+			top = p
+		}
+	}
+	return top
+}
+
+// EVWithoutTopPrize is EV with the top tier's remaining prizes counted as
+// losing tickets. When it is far worse than EV, the game's value hangs on
+// the few unclaimed top prizes.
+func (g *Game) EVWithoutTopPrize() float64 {
+	ev := g.EV()
+	remainingTickets := g.RemainingTickets()
+	top := g.TopTier()
+	if remainingTickets == 0 || top.RemainingCount <= 0 {
+		return ev
+	}
+	return ev + float64(top.RemainingCount)*g.TierValue(top)/float64(remainingTickets)
+}
+
+// BreakEvenTopPrizes is how many top prizes would need to still be unclaimed
+// for the game to break even, compared against the top tier's RemainingCount.
+func (g *Game) BreakEvenTopPrizes() int {
+	top := g.TopTier()
+	if g.TierValue(top) == 0 {
+		return 0
+	}
+	return top.RemainingCount + int(math.Ceil(g.breakEvenShortfall()/g.TierValue(top)))
+}
+
+// TierOdds derives "1 in N" odds for a tier from the estimated ticket counts:
+// at launch, and for a ticket bought today. Zero when the tier has no prizes.
+func (g *Game) TierOdds(p PrizeTier) (original, current float64) {
+	if p.OriginalCount > 0 {
+		original = float64(g.OriginalTickets()) / float64(p.OriginalCount)
+	}
+	if p.RemainingCount > 0 {
+		current = float64(g.RemainingTickets()) / float64(p.RemainingCount)
+	}
+	return original, current
+}
+
+// OriginalPrizeMoney is the total dollar value of every prize printed for the game.
+func (g *Game) OriginalPrizeMoney() int {
+	var total float64
+	for _, p := range g.PrizeTiers {
+		total += g.TierValue(p) * float64(p.OriginalCount)
+	}
+	return int(math.Round(total))
+}
+
+// RemainingPrizeMoney is the total dollar value of the prizes still unclaimed.
+func (g *Game) RemainingPrizeMoney() int {
+	var total float64
+	for _, p := range g.PrizeTiers {
+		total += g.TierValue(p) * float64(p.RemainingCount)
+	}
+	return int(math.Round(total))
+}
+
+// PayoutRemaining is the fraction of the original prize money still unclaimed.
+// Unlike TotalRemainingPrizes/TotalOriginalPrizes this is dollar weighted, so a
+// claimed top prize moves it far more than a claimed break-even prize.
+func (g *Game) PayoutRemaining() float64 {
+	orig := g.OriginalPrizeMoney()
+	if orig == 0 {
+		return 0
+	}
+	return float64(g.RemainingPrizeMoney()) / float64(orig)
+}
+
+// validPrices are the ticket prices Mississippi sells scratch-offs at.
+var validPrices = map[int]bool{1: true, 2: true, 3: true, 5: true, 10: true, 20: true, 25: true, 30: true, 50: true}
+
+// CheckPrice flags prices outside validPrices, which almost always means the
+// details table was misread rather than a new price point.
+func CheckPrice(g Game) error {
+	if !validPrices[g.Price] {
+		return fmt.Errorf("%s: ticket price $%d is not a known price point", g.URL, g.Price)
+	}
+	return nil
+}
+
+// Round fixes a metric to the given number of decimals so outputs don't
+// change on float noise between runs.
+func Round(x float64, places int) float64 {
+	p := math.Pow(10, float64(places))
+	return math.Round(x*p) / p
+}
@@ -0,0 +1,42 @@
+package model
+
+// Settings are a run's choices behind a game's numbers: how its tickets are
+// estimated, how its non-cash prizes are valued and which way its EV is
+// reported. The zero value is OddsModel, face values and NetLoss.
+type Settings struct {
+	Estimator Estimator // OddsModel when nil
+	Valuation Valuation
+	EVSign    EVSign
+}
+
+// ApplySettings has every game in games worked out under s. Games carry their
+// settings so that whatever reads them, whether a ranking, a simulation or an
+// output, uses the same ones.
+func ApplySettings(games []Game, s *Settings) {
+	for i := range games {
+		games[i].Settings = s
+	}
+}
+
+// defaultSettings stand in for a game's nil Settings.
+var defaultSettings Settings
+
+func (g *Game) settings() *Settings {
+	if g.Settings == nil {
+		return &defaultSettings
+	}
+	return g.Settings
+}
+
+// Estimator is the model behind OriginalTickets and RemainingTickets.
+func (g *Game) Estimator() Estimator {
+	if e := g.settings().Estimator; e != nil {
+		return e
+	}
+	return OddsModel{}
+}
+
+// EVSign is how the game's EV is reported, see ReportedEV.
+func (g *Game) EVSign() EVSign {
+	return g.settings().EVSign
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Valuation turns non-cash prizes (vehicles, trips, merchandise, free
+// tickets) into dollars for the EV math.
+type Valuation struct {
+	Values     map[string]float64 // by tier Tag, e.g. "MOTORCYCLE": 12000
+	Haircut    float64            // fraction knocked off the stated value of merchandise
+	FreeTicket string             // FreeTicketAtPrice (the default when empty) or FreeTicketAtEV
+}
+
+// Ways to value a prize of a free ticket.
+const (
+	FreeTicketAtPrice = "price" // what the ticket costs
+	FreeTicketAtEV    = "ev"    // what the replacement ticket is expected to win
+)
+
+// cashTags decorate a cash prize rather than replace it.
+var cashTags = []string{"WIN ALL", "BONUS", "CASH"}
+
+// ticketTags are the ways the site words a prize of a ticket. Other tags with
+// FREE in them, e.g. "FREE GAS", are merchandise.
+var ticketTags = []string{"FREE TICKET", "FREE TICKETS", "TICKET", "TICKETS"}
+
+// IsFreeTicket reports whether the tier pays a ticket instead of cash, e.g.
+// "FREE TICKET". The parser sets such a tier's Value to the ticket's price.
+func (p PrizeTier) IsFreeTicket() bool {
+	return slices.Contains(ticketTags, p.Tag)
+}
+
+// IsMerchandise reports whether the tier pays something other than cash or
+// a ticket.
+func (p PrizeTier) IsMerchandise() bool {
+	if p.Tag == "" || p.IsFreeTicket() {
+		return false
+	}
+	for _, t := range cashTags {
+		if strings.Contains(p.Tag, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// CashValue is what p is worth in the EV math under v: an explicit valuation
+// if one is configured for its tag, the stated value less the haircut for
+// merchandise, and the face value otherwise. Game.TierValue applies
+// FreeTicketAtEV on top.
+func (v Valuation) CashValue(p PrizeTier) float64 {
+	if value, ok := v.Values[p.Tag]; ok {
+		return value
+	}
+	if p.IsMerchandise() {
+		return float64(p.Value) * (1 - v.Haircut)
+	}
+	return float64(p.Value)
+}
+
+// TierValue is what p is worth on a ticket of g: its CashValue under the
+// game's Valuation, except that with FreeTicket set to FreeTicketAtEV a free
+// ticket is worth what the replacement ticket is expected to win.
+func (g *Game) TierValue(p PrizeTier) float64 {
+	v := g.settings().Valuation
+	if !v.valuedAtEV(p) {
+		return v.CashValue(p)
+	}
+	return float64(p.Value) * g.freeTicketReturn()
+}
+
+// valuedAtEV reports whether p is a free ticket valued by FreeTicketAtEV
+// rather than by an explicit -prize-value or its price.
+func (v Valuation) valuedAtEV(p PrizeTier) bool {
+	if v.FreeTicket != FreeTicketAtEV || !p.IsFreeTicket() {
+		return false
+	}
+	_, ok := v.Values[p.Tag]
+	return !ok
+}
+
+// freeTicketReturn is what a dollar of g's tickets is expected to win when
+// free tickets are worth their own expected winnings: the r solving
+// r * price = cash + r * free, where cash is a ticket's expected cash
+// winnings and free the expected face value of the free tickets it wins.
+func (g *Game) freeTicketReturn() float64 {
+	remaining := g.RemainingTickets()
+	if remaining == 0 {
+		return 0
+	}
+	v := g.settings().Valuation
+	var cash, free float64
+	for _, p := range g.PrizeTiers {
+		if p.RemainingCount <= 0 {
+			continue
+		}
+		prob := float64(p.RemainingCount) / float64(remaining)
+		if v.valuedAtEV(p) {
+			free += prob * float64(p.Value)
+		} else if value := v.CashValue(p); value > 0 {
+			cash += prob * value
+		}
+	}
+	if free >= float64(g.Price) {
+		return 1 // every ticket wins another ticket; count them at face value
+	}
+	return cash / (float64(g.Price) - free)
+}
+
+// ParsePrizeValue parses a "TAG=dollars" valuation flag.
+func ParsePrizeValue(def string) (tag string, value float64, err error) {
+	tag, v, ok := strings.Cut(def, "=")
+	if !ok {
+		return "", 0, fmt.Errorf("prize value %q: want TAG=dollars", def)
+	}
+	value, err = strconv.ParseFloat(strings.TrimPrefix(strings.TrimSpace(v), "$"), 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("prize value %q: %w", def, err)
+	}
+	return strings.ToUpper(strings.TrimSpace(tag)), value, nil
+}
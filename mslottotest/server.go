@@ -0,0 +1,155 @@
+// Package mslottotest serves game pages from an in-memory httptest.Server so
+// a scrape can run end to end without touching the lottery's site:
+//
+//	srv := mslottotest.NewServer(pages...)
+//	defer srv.Close()
+//	defer srv.Install()()
+//	p := pipeline.New(pipeline.Options{Concurrency: 4}, write)
+//	err := p.Run(&pipeline.Run{Started: time.Now(), Digest: &notify.Digest{}})
+package mslottotest
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"msLotto/scrape"
+)
+
+// indexPath is where the site lists its active games.
+const indexPath = "/gamestatus/active/"
+
+// siteURL is replaced with the server's URL in a recorded index, so its
+// links lead back to the server.
+const siteURL = "https://www.mslottery.com"
+
+// Page is one game page, served at /games/<Slug>/. The scraper takes the
+// game's name from the slug, dashes for spaces.
+type Page struct {
+	Slug string
+	Body []byte
+}
+
+// Server serves an index of active games and the game pages it links to.
+type Server struct {
+	*httptest.Server
+
+	// Index, when set, is served in place of the generated index, with
+	// links to www.mslottery.com pointed at the server.
+	Index []byte
+
+	pages map[string][]byte
+}
+
+// NewServer starts a server for pages. Close it when done.
+func NewServer(pages ...Page) *Server {
+	s := &Server{pages: map[string][]byte{}}
+	for _, p := range pages {
+		s.pages[p.Slug] = p.Body
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(indexPath, s.serveIndex)
+	mux.HandleFunc("/games/", s.serveGame)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// NewServerFromDir starts a server for every .html page in dir, such as the
+// raw/ folder -keep-artifacts writes, serving index.html as the index when
+// the folder has one.
+func NewServerFromDir(dir string) (*Server, error) {
+	pages, err := LoadPages(dir)
+	if err != nil {
+		return nil, err
+	}
+	index, err := os.ReadFile(filepath.Join(dir, "index.html"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	s := NewServer(pages...)
+	s.Index = index
+	return s, nil
+}
+
+// LoadPages reads every .html file in dir but index.html as a page whose
+// slug is the file name.
+func LoadPages(dir string) ([]Page, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, err
+	}
+	var pages []Page
+	for _, path := range paths {
+		if filepath.Base(path) == "index.html" {
+			continue
+		}
+		body, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, Page{Slug: strings.TrimSuffix(filepath.Base(path), ".html"), Body: body})
+	}
+	if len(pages) == 0 {
+		return nil, fmt.Errorf("no .html pages in %s", dir)
+	}
+	return pages, nil
+}
+
+// IndexURL is the server's list of active games, for scrape.StartURL.
+func (s *Server) IndexURL() string {
+	return s.URL + indexPath
+}
+
+// GameURL is where the page with slug is served.
+func (s *Server) GameURL(slug string) string {
+	return s.URL + "/games/" + slug + "/"
+}
+
+// Install points scrape.StartURL and scrape.DefaultFetcher at the server and
+// returns a func that puts them back.
+func (s *Server) Install() (restore func()) {
+	startURL, fetcher := scrape.StartURL, scrape.DefaultFetcher
+	scrape.StartURL = s.IndexURL()
+	scrape.DefaultFetcher = scrape.HTTPFetcher{Client: s.Client()}
+	return func() {
+		scrape.StartURL, scrape.DefaultFetcher = startURL, fetcher
+	}
+}
+
+func (s *Server) serveIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if s.Index != nil {
+		w.Write(bytes.ReplaceAll(s.Index, []byte(siteURL), []byte(s.URL)))
+		return
+	}
+	slugs := make([]string, 0, len(s.pages))
+	for slug := range s.pages {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+	var b strings.Builder
+	b.WriteString("<html><body>\n")
+	for _, slug := range slugs {
+		fmt.Fprintf(&b, "<div class=\"col-lg-3 gamebox\"><a href=\"%s\">%s</a></div>\n",
+			html.EscapeString(s.GameURL(slug)), html.EscapeString(strings.ReplaceAll(slug, "-", " ")))
+	}
+	b.WriteString("</body></html>\n")
+	w.Write([]byte(b.String()))
+}
+
+func (s *Server) serveGame(w http.ResponseWriter, r *http.Request) {
+	slug := strings.Trim(strings.TrimPrefix(r.URL.Path, "/games/"), "/")
+	body, ok := s.pages[slug]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(body)
+}
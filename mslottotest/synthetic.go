@@ -0,0 +1,32 @@
+package mslottotest
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// SyntheticPage renders a made-up game page numbered 1000+n in the shape the
+// site uses: a labelled details table followed by a prize table with tiers
+// rows.
+func SyntheticPage(n, tiers int, r *rand.Rand) []byte {
+	prices := []int{1, 2, 3, 5, 10, 20, 25, 30, 50}
+	price := prices[r.IntN(len(prices))]
+
+	var b strings.Builder
+	b.WriteString("<html><body><h2>Game Details</h2><table>\n")
+	fmt.Fprintf(&b, "<tr><td>Ticket Price</td><td>$%d</td></tr>\n", price)
+	fmt.Fprintf(&b, "<tr><td>Overall Odds</td><td>1:%.2f</td></tr>\n", 3+r.Float64()*2)
+	fmt.Fprintf(&b, "<tr><td>Launch Date</td><td>%d/%d/2025</td></tr>\n", 1+r.IntN(12), 1+r.IntN(28))
+	fmt.Fprintf(&b, "<tr><td>Game Number</td><td>%d</td></tr>\n", 1000+n)
+	b.WriteString("</table>\n<h2>Prize Structure</h2><table>\n")
+	b.WriteString("<tr><th>Prize Amount</th><th>Total Prizes</th><th>Prizes Remaining</th></tr>\n")
+	value, count := price, 200000+r.IntN(100000)
+	for range tiers {
+		fmt.Fprintf(&b, "<tr><td>$%d</td><td>%d</td><td>%d</td></tr>\n", value, count, r.IntN(count+1))
+		value *= 2 + r.IntN(3)
+		count = max(count/(2+r.IntN(3)), 1)
+	}
+	b.WriteString("</table></body></html>\n")
+	return []byte(b.String())
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"msLotto/model"
+)
+
+// hookTimeout bounds how long a game hook may run or wait for its webhook.
+const hookTimeout = 30 * time.Second
+
+// GameHook hands a game to a webhook or a command, e.g. to announce a new
+// game. Target is an http(s) URL, which gets the game POSTed as JSON, or a
+// shell command, which gets the JSON on stdin and MSLOTTO_GAME_NAME,
+// MSLOTTO_GAME_NUMBER, MSLOTTO_GAME_PRICE and MSLOTTO_GAME_URL in its
+// environment.
+type GameHook struct {
+	Target string
+}
+
+// Fire runs the hook for g.
+func (h GameHook) Fire(g model.Game) error {
+	body, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), hookTimeout)
+	defer cancel()
+
+	if strings.HasPrefix(h.Target, "http://") || strings.HasPrefix(h.Target, "https://") {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.Target, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		return doNotify("webhook", req)
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", h.Target)
+	cmd.Stdin = bytes.NewReader(body)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(),
+		"MSLOTTO_GAME_NAME="+g.Name,
+		"MSLOTTO_GAME_NUMBER="+strconv.Itoa(g.GameNumber),
+		"MSLOTTO_GAME_PRICE="+strconv.Itoa(g.Price),
+		"MSLOTTO_GAME_URL="+g.URL,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("hook %q: %w", h.Target, err)
+	}
+	return nil
+}
@@ -0,0 +1,287 @@
+// Package notify sends run summaries and events to push services.
+package notify
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/model"
+)
+
+// Notifier delivers a short message to an outside service.
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// NotifierFactory builds a Notifier from the options given on the command line.
+type NotifierFactory func(opts map[string]string) (Notifier, error)
+
+var notifierFactories = map[string]NotifierFactory{}
+
+// RegisterNotifier makes a notifier available to -notify under name. Extra
+// notifiers only need a file that calls this from init.
+func RegisterNotifier(name string, f NotifierFactory) {
+	if _, dup := notifierFactories[name]; dup {
+		panic("notifier registered twice: " + name)
+	}
+	notifierFactories[name] = f
+}
+
+func init() {
+	RegisterNotifier("ntfy", newNtfyNotifier)
+	RegisterNotifier("pushover", newPushoverNotifier)
+}
+
+// NewNotifier builds a notifier from a spec such as
+// "ntfy:topic=mslotto,token=tk_abc" or "pushover:token=...,user=...".
+// ntfy also takes server= for a self-hosted instance, and user= and
+// password= in place of a token.
+func NewNotifier(spec string) (Notifier, error) {
+	name, rest, _ := strings.Cut(spec, ":")
+	f, ok := notifierFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown notifier %q", name)
+	}
+	opts := map[string]string{}
+	for _, kv := range strings.Split(rest, ",") {
+		if kv == "" {
+			continue
+		}
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("notifier %s: option %q is not key=value", name, kv)
+		}
+		opts[k] = v
+	}
+	n, err := f(opts)
+	if err != nil {
+		return nil, err
+	}
+	return Configured{Notifier: n, ID: specID(spec)}, nil
+}
+
+// RunSummary is the message sent at the end of a run: the n games with the
+// smallest expected loss, leaving out dead games, each with its price
+// percentile among peers (the games themselves when nil), and how the
+// market's remaining prize money splits by prize size.
+func RunSummary(games []model.Game, peers analyze.PricePeers, n int) (title, message string) {
+	if peers == nil {
+		peers = analyze.NewPricePeers(games, nil)
+	}
+	best, _ := analyze.SplitDead(games)
+	analyze.SortByEV(best, false)
+	if len(best) > n {
+		best = best[:n]
+	}
+
+	var b strings.Builder
+	for i, g := range best {
+		fmt.Fprintf(&b, "%d. %s ($%d) EV %.2f, %s percentile of $%d games\n", i+1, g.Name, g.Price, g.ReportedEV(), ordinal(int(peers.Percentile(g))), g.Price)
+	}
+	if len(games) > 0 {
+		fmt.Fprintf(&b, "Prize money left: %s\n", analyze.FormatBuckets(analyze.RemainingByBucket(games...)))
+	}
+	return fmt.Sprintf("mslotto: %d games scraped", len(games)), strings.TrimSpace(b.String())
+}
+
+func ordinal(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+type ntfyNotifier struct {
+	server   string
+	topic    string
+	token    string
+	user     string // basic auth, for self-hosted servers without access tokens
+	password string
+}
+
+func newNtfyNotifier(opts map[string]string) (Notifier, error) {
+	n := ntfyNotifier{server: "https://ntfy.sh", topic: opts["topic"], token: opts["token"], user: opts["user"], password: opts["password"]}
+	if s := opts["server"]; s != "" {
+		n.server = strings.TrimRight(s, "/")
+	}
+	if n.topic == "" {
+		return nil, fmt.Errorf("notifier ntfy: topic is required")
+	}
+	if n.token != "" && n.user != "" {
+		return nil, fmt.Errorf("notifier ntfy: use either token or user and password, not both")
+	}
+	return n, nil
+}
+
+func (n ntfyNotifier) Notify(title, message string) error {
+	req, err := http.NewRequest(http.MethodPost, n.server+"/"+n.topic, strings.NewReader(message))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Title", title)
+	switch {
+	case n.token != "":
+		req.Header.Set("Authorization", "Bearer "+n.token)
+	case n.user != "":
+		req.SetBasicAuth(n.user, n.password)
+	}
+	return doNotify("ntfy", req)
+}
+
+type pushoverNotifier struct {
+	token string
+	user  string
+}
+
+func newPushoverNotifier(opts map[string]string) (Notifier, error) {
+	n := pushoverNotifier{token: opts["token"], user: opts["user"]}
+	if n.token == "" || n.user == "" {
+		return nil, fmt.Errorf("notifier pushover: token and user are required")
+	}
+	return n, nil
+}
+
+func (n pushoverNotifier) Notify(title, message string) error {
+	form := url.Values{
+		"token":   {n.token},
+		"user":    {n.user},
+		"title":   {title},
+		"message": {message},
+	}
+	req, err := http.NewRequest(http.MethodPost, "https://api.pushover.net/1/messages.json", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return doNotify("pushover", req)
+}
+
+// notifyClient sends every notifier's request. The timeout keeps a service
+// that never answers from holding up the end of a run.
+var notifyClient = &http.Client{Timeout: 30 * time.Second}
+
+func doNotify(name string, req *http.Request) error {
+	resp, err := notifyClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("notifier %s: %w", name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("notifier %s: %s", name, resp.Status)
+	}
+	return nil
+}
+
+// Severity ranks digest events; critical events can skip the digest.
+type Severity int
+
+const (
+	Info Severity = iota
+	Warning
+	Critical
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	}
+	return "INFO"
+}
+
+// Event is one thing worth telling the notifiers about.
+type Event struct {
+	Severity Severity
+	Title    string
+	Message  string
+}
+
+// Digest collects a run's events and sends them as one message per notifier.
+// With Instant set, critical events are also delivered the moment they happen.
+// With a Queue, messages a Configured notifier fails to deliver are kept for
+// a later retry. RunID, when set, closes every message.
+type Digest struct {
+	Notifiers []Notifier
+	Instant   bool
+	Queue     *RetryQueue
+	RunID     string
+	Log       *log.Logger // where failed sends are logged, the standard logger when nil
+
+	mu     sync.Mutex
+	events []Event
+}
+
+// Add records an event for the digest.
+func (d *Digest) Add(e Event) {
+	if d.Instant && e.Severity == Critical {
+		d.send(e.Severity.String()+": "+e.Title, e.Message)
+	}
+	d.mu.Lock()
+	d.events = append(d.events, e)
+	d.mu.Unlock()
+}
+
+// Events returns a copy of the events recorded so far.
+func (d *Digest) Events() []Event {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]Event(nil), d.events...)
+}
+
+// Flush sends every recorded event, most severe first, and clears the digest.
+func (d *Digest) Flush() {
+	d.mu.Lock()
+	events := d.events
+	d.events = nil
+	d.mu.Unlock()
+	if len(events) == 0 {
+		return
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Severity > events[j].Severity
+	})
+	var b strings.Builder
+	for _, e := range events {
+		fmt.Fprintf(&b, "[%s] %s\n%s\n\n", e.Severity, e.Title, e.Message)
+	}
+	d.send(fmt.Sprintf("mslotto: %d event(s) this run", len(events)), strings.TrimSpace(b.String()))
+}
+
+// logger is l, or the standard logger when l is nil.
+func logger(l *log.Logger) *log.Logger {
+	if l != nil {
+		return l
+	}
+	return log.Default()
+}
+
+func (d *Digest) send(title, message string) {
+	if d.RunID != "" {
+		message += "\n\nrun " + d.RunID
+	}
+	for _, n := range d.Notifiers {
+		if err := n.Notify(title, message); err != nil {
+			logger(d.Log).Println("Error sending notification:", err)
+			if c, ok := n.(Configured); ok && d.Queue != nil {
+				d.Queue.Add(c.ID, title, message, err, time.Now())
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Configured is a notifier built from a -notify spec. Its ID, a hash of the
+// spec, files undelivered messages in a RetryQueue without writing the
+// spec's credentials to disk.
+type Configured struct {
+	Notifier
+	ID string
+}
+
+func specID(spec string) string {
+	sum := sha256.Sum256([]byte(spec))
+	return hex.EncodeToString(sum[:8])
+}
+
+// Retry backoff: the first retry waits retryBase, each later one twice as
+// long up to retryMax. Messages still undelivered after retryMaxAge are
+// dropped.
+const (
+	retryBase   = time.Minute
+	retryMax    = 6 * time.Hour
+	retryMaxAge = 7 * 24 * time.Hour
+)
+
+// backoff is the wait after the given number of failed attempts.
+func backoff(attempts int) time.Duration {
+	d := retryBase
+	for i := 1; i < attempts && d < retryMax; i++ {
+		d *= 2
+	}
+	return min(d, retryMax)
+}
+
+// QueuedMessage is a notification a notifier failed to deliver.
+type QueuedMessage struct {
+	Notifier    string    `json:"notifier"` // Configured.ID
+	Title       string    `json:"title"`
+	Message     string    `json:"message"`
+	FirstFailed time.Time `json:"first_failed"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"next_attempt"`
+	LastError   string    `json:"last_error"`
+}
+
+// RetryQueue keeps undelivered notifications in a file between runs, so an
+// alert sent while a push service is down goes out on a later run instead
+// of being lost.
+type RetryQueue struct {
+	Pending []QueuedMessage `json:"pending"`
+	Log     *log.Logger     `json:"-"` // where Retry logs, the standard logger when nil
+
+	path string
+	mu   sync.Mutex
+}
+
+// LoadRetryQueue reads the queue file at path. A missing file is an empty
+// queue.
+func LoadRetryQueue(path string) (*RetryQueue, error) {
+	q := &RetryQueue{path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return q, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, q); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+// Add queues a message that failed to go out through the notifier id.
+func (q *RetryQueue) Add(id, title, message string, err error, now time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.Pending = append(q.Pending, QueuedMessage{
+		Notifier:    id,
+		Title:       title,
+		Message:     message,
+		FirstFailed: now,
+		Attempts:    1,
+		NextAttempt: now.Add(backoff(1)),
+		LastError:   err.Error(),
+	})
+}
+
+// Retry redelivers every message that is due through its notifier, backing
+// off the ones that fail again. Messages for notifiers no longer configured
+// wait until they age out.
+func (q *RetryQueue) Retry(notifiers []Notifier, now time.Time) {
+	byID := map[string]Notifier{}
+	for _, n := range notifiers {
+		if c, ok := n.(Configured); ok {
+			byID[c.ID] = c
+		}
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	kept := q.Pending[:0]
+	for _, m := range q.Pending {
+		if now.Sub(m.FirstFailed) > retryMaxAge {
+			logger(q.Log).Printf("Dropping notification %q after %d failed attempts: %s", m.Title, m.Attempts, m.LastError)
+			continue
+		}
+		n, ok := byID[m.Notifier]
+		if !ok || now.Before(m.NextAttempt) {
+			kept = append(kept, m)
+			continue
+		}
+		if err := n.Notify(m.Title, m.Message); err != nil {
+			m.Attempts++
+			m.NextAttempt = now.Add(backoff(m.Attempts))
+			m.LastError = err.Error()
+			kept = append(kept, m)
+			continue
+		}
+		logger(q.Log).Printf("Delivered notification %q after %d failed attempt(s)", m.Title, m.Attempts)
+	}
+	q.Pending = kept
+}
+
+// Save writes the queue back to the file it was loaded from, removing the
+// file once nothing is pending.
+func (q *RetryQueue) Save() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.Pending) == 0 {
+		if err := os.Remove(q.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(q.path, append(data, '\n'), 0o600)
+}
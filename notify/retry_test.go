@@ -0,0 +1,97 @@
+package notify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeNotifier records what it delivers, failing while err is set.
+type fakeNotifier struct {
+	err  error
+	sent []string
+}
+
+func (f *fakeNotifier) Notify(title, message string) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, title)
+	return nil
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Minute},
+		{2, 2 * time.Minute},
+		{3, 4 * time.Minute},
+		{10, 512 * time.Minute},
+		{100, retryMax},
+	}
+	for _, tt := range tests {
+		if got := backoff(tt.attempts); got != min(tt.want, retryMax) {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempts, got, min(tt.want, retryMax))
+		}
+	}
+}
+
+func TestRetryQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue.json")
+	q, err := LoadRetryQueue(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	down := errors.New("service unavailable")
+	push := &fakeNotifier{err: down}
+	notifiers := []Notifier{Configured{Notifier: push, ID: specID("push://token")}}
+
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	q.Add(specID("push://token"), "Jackpot", "details", down, start)
+	q.Add(specID("gone://"), "Orphan", "details", down, start)
+	if err := q.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Reloaded, nothing is due before the first backoff has passed.
+	if q, err = LoadRetryQueue(path); err != nil {
+		t.Fatal(err)
+	}
+	q.Retry(notifiers, start.Add(30*time.Second))
+	if len(q.Pending) != 2 || q.Pending[0].Attempts != 1 {
+		t.Fatalf("pending = %+v, want both messages untried", q.Pending)
+	}
+
+	// Due, but the service is still down: backed off again.
+	q.Retry(notifiers, start.Add(time.Minute))
+	if m := q.Pending[0]; m.Attempts != 2 || !m.NextAttempt.Equal(start.Add(3*time.Minute)) {
+		t.Fatalf("after a second failure = %+v", m)
+	}
+
+	// Back up: delivered and dropped from the queue. The message for a
+	// notifier no longer configured stays.
+	push.err = nil
+	q.Retry(notifiers, start.Add(3*time.Minute))
+	if len(push.sent) != 1 || push.sent[0] != "Jackpot" {
+		t.Errorf("delivered %v, want [Jackpot]", push.sent)
+	}
+	if len(q.Pending) != 1 || q.Pending[0].Title != "Orphan" {
+		t.Fatalf("pending = %+v, want the orphan only", q.Pending)
+	}
+
+	// The orphan ages out.
+	q.Retry(notifiers, start.Add(retryMaxAge+time.Second))
+	if len(q.Pending) != 0 {
+		t.Errorf("pending = %+v, want nothing", q.Pending)
+	}
+	if err := q.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("empty queue left its file behind: %v", err)
+	}
+}
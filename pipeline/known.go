@@ -0,0 +1,164 @@
+package pipeline
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"msLotto/analyze"
+	"msLotto/model"
+	"msLotto/scrape"
+)
+
+// KnownGames remembers every game earlier runs have seen, so a game that
+// shows up for the first time can be flagged the day it launches, and a game
+// that leaves the active list is archived with its final prize counts.
+type KnownGames struct {
+	Games map[string]KnownGame `json:"games"` // by analyze.GameKey
+
+	path string
+}
+
+// KnownGame is a game as of the last run that saw it.
+type KnownGame struct {
+	Game      model.Game `json:"game"`
+	FirstSeen time.Time  `json:"first_seen"`
+	LastSeen  time.Time  `json:"last_seen"`
+	Ended     time.Time  `json:"ended,omitzero"`      // when the game left the index
+	PageHash  string     `json:"page_hash,omitempty"` // of the page Game was parsed from, see Unchanged
+}
+
+// pageHash identifies a page body's content.
+func pageHash(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
+// Unchanged returns the game the last run parsed from p's URL when the page
+// is byte for byte the same and the parser hasn't changed since, so it
+// needn't be parsed again.
+func (k *KnownGames) Unchanged(p Page) (model.Game, bool) {
+	hash := pageHash(p.Body)
+	for _, seen := range k.Games {
+		if seen.Game.URL == p.URL && seen.PageHash == hash && seen.Ended.IsZero() && seen.Game.ParserVersion == scrape.ParserVersion {
+			return seen.Game, true
+		}
+	}
+	return model.Game{}, false
+}
+
+// RecordPages remembers the hash of each page this run parsed, for
+// Unchanged next run. Call it after Observe.
+func (k *KnownGames) RecordPages(pages []Page) {
+	hashes := map[string]string{}
+	for _, p := range pages {
+		hashes[p.URL] = pageHash(p.Body)
+	}
+	for key, seen := range k.Games {
+		if hash, ok := hashes[seen.Game.URL]; ok {
+			seen.PageHash = hash
+			k.Games[key] = seen
+		}
+	}
+}
+
+// LoadKnownGames reads the known games file at path. A missing file is a
+// first run, which flags nothing as new.
+func LoadKnownGames(path string) (*KnownGames, error) {
+	k := &KnownGames{Games: map[string]KnownGame{}, path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return k, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, err
+	}
+	if k.Games == nil {
+		k.Games = map[string]KnownGame{}
+	}
+	return k, nil
+}
+
+// Save writes the known games back to the file they were loaded from.
+func (k *KnownGames) Save() error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(k.path, append(data, '\n'), 0o644)
+}
+
+// IsNew reports whether Observe would flag g as new: no earlier run saw it,
+// and this isn't the first run.
+func (k *KnownGames) IsNew(g model.Game) bool {
+	_, ok := k.Games[analyze.GameKey(g)]
+	return !ok && len(k.Games) > 0
+}
+
+// Observe records this run's games and sets New on the ones no earlier run
+// saw, returning them as added. Nothing is new on the first run. Known games
+// whose link is missing from index are marked ended and returned as ended,
+// with Ended set;
+// with no index or no games, e.g. when the site was down, nothing ends.
+func (k *KnownGames) Observe(games []model.Game, index []string, now time.Time) (added, ended []model.Game) {
+	first := len(k.Games) == 0
+	for i := range games {
+		g := &games[i]
+		key := analyze.GameKey(*g)
+		seen, ok := k.Games[key]
+		g.New = !ok && !first
+		if g.New {
+			added = append(added, *g)
+		}
+		if !ok {
+			seen.FirstSeen = now
+		}
+		seen.Game, seen.LastSeen, seen.Ended = *g, now, time.Time{}
+		seen.Game.New = false
+		k.Games[key] = seen
+	}
+
+	if len(index) == 0 || len(games) == 0 {
+		return added, nil
+	}
+	active := map[string]bool{}
+	for _, l := range index {
+		active[l] = true
+	}
+	for key, seen := range k.Games {
+		if seen.Ended.IsZero() && !active[seen.Game.URL] {
+			seen.Ended = now
+			k.Games[key] = seen
+			g := seen.Game
+			g.Ended = now
+			ended = append(ended, g)
+		}
+	}
+	return added, ended
+}
+
+// Ended returns the archived games with their final prize counts and Ended
+// set, most recently ended first.
+func (k *KnownGames) Ended() []model.Game {
+	var games []model.Game
+	for _, seen := range k.Games {
+		if !seen.Ended.IsZero() {
+			g := seen.Game
+			g.Ended = seen.Ended
+			games = append(games, g)
+		}
+	}
+	sort.Slice(games, func(i, j int) bool {
+		if !games[i].Ended.Equal(games[j].Ended) {
+			return games[i].Ended.After(games[j].Ended)
+		}
+		return games[i].Name < games[j].Name
+	})
+	return games
+}
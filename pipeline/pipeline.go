@@ -0,0 +1,424 @@
+// Package pipeline strings the scrape together from discovery through
+// writing as a series of replaceable stages.
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"msLotto/analyze"
+	"msLotto/journal"
+	"msLotto/model"
+	"msLotto/notify"
+	"msLotto/scrape"
+)
+
+// Page is a fetched game page.
+type Page struct {
+	URL  string
+	Body []byte
+}
+
+// Run is the state handed from one pipeline stage to the next.
+type Run struct {
+	Started time.Time
+	ID      string        // see NewRunID
+	Links   <-chan string // discovered game links, consumed by fetch
+	Index   []string      // every discovered link, set by discover when it reads the whole index first, else by fetch
+	Fetched <-chan Page   // downloaded pages, consumed by parse
+	Pages   []Page        // every page parse received
+	Reused  int           // pages parse took from Known unchanged instead of parsing again
+	Games   []model.Game
+	Ended   []model.Game       // games that left the index this run, set by known-games
+	History []journal.Snapshot // journaled snapshots, when loaded, for historical context
+	Peers   analyze.PricePeers // what each game's price percentile ranks against, set by analyze
+	Digest  *notify.Digest
+	Log     *log.Logger     // where the stages log, the standard logger when nil
+	Context context.Context // carries the running stage's trace span, see StartTracing
+
+	stage trace.Span // the running stage's span, until Detach hands it off
+}
+
+// Detach is for a stage that hands its work to a goroutine feeding the next
+// stage and returns straight away: Run then leaves the stage's span open,
+// and the returned func ends it once the goroutine is done.
+func (r *Run) Detach() (end func()) {
+	span := r.stage
+	r.stage = nil
+	return func() { span.End() }
+}
+
+// logger is where the stages log.
+func (r *Run) logger() *log.Logger {
+	if r.Log != nil {
+		return r.Log
+	}
+	return log.Default()
+}
+
+// Stage is one step of the scrape pipeline.
+type Stage struct {
+	Name string
+	Run  func(r *Run) error
+}
+
+// Middleware wraps a stage, e.g. to time it or to act on its result.
+type Middleware func(next Stage) Stage
+
+// Pipeline runs its stages in order: discover, fetch, parse, analyze, write.
+// Custom steps are added with InsertAfter and cross-cutting hooks with Use.
+type Pipeline struct {
+	Stages     []Stage
+	middleware []Middleware
+}
+
+// Options configure the standard stages.
+type Options struct {
+	Concurrency int
+	PDF         bool // enrich games from their PDF game sheets
+	Shuffle     bool // fetch games in random order instead of index order
+
+	// Fetcher is what pages are fetched through, scrape.DefaultFetcher when
+	// nil.
+	Fetcher scrape.Fetcher
+
+	// MaxDuration stops new fetches this long after the run started, 0 for
+	// no limit. Pages already being fetched are still finished.
+	MaxDuration time.Duration
+	// Progress, when set, fetches the stalest games first and fills in the
+	// games a time-boxed run didn't reach from their last scrape.
+	Progress *Progress
+	// OnGame is called with each game as soon as it is parsed, before the
+	// run is analyzed, New already set when Known is. Games carried over by
+	// Progress follow once the fetches are done. Calls never overlap.
+	OnGame func(g model.Game)
+	// Known, when set, flags games no earlier run has seen as New and
+	// reports them to the digest and OnNewGame, and archives the games that
+	// left the index as ended.
+	Known     *KnownGames
+	OnNewGame func(g model.Game)
+	// Settings are how the games' numbers are worked out, nil for the
+	// defaults. Games carried over by Progress get them too.
+	Settings *model.Settings
+	// LastOdds, by game number, fill in the overall odds of a page that is
+	// missing them, e.g. from the -db history. Each carried-forward value is
+	// reported to the digest.
+	LastOdds map[int]float64
+}
+
+// fetcher is what the stages fetch pages through.
+func (o Options) fetcher() scrape.Fetcher {
+	if o.Fetcher != nil {
+		return o.Fetcher
+	}
+	return scrape.DefaultFetcher
+}
+
+// New returns the standard stages. write receives the analyzed run.
+func New(opts Options, write func(r *Run) error) *Pipeline {
+	p := &Pipeline{Stages: []Stage{
+		{Name: "discover", Run: discoverStage(opts)},
+		{Name: "fetch", Run: fetchStage(opts)},
+		{Name: "parse", Run: parseStage(opts)},
+		{Name: "analyze", Run: analyzeStage},
+		{Name: "write", Run: write},
+	}}
+	if opts.Progress != nil {
+		p.InsertAfter("parse", Stage{Name: "progress", Run: progressStage(opts)})
+	}
+	if opts.Known != nil {
+		// After progress, so games carried over from an earlier scrape are
+		// checked too.
+		after := "parse"
+		if opts.Progress != nil {
+			after = "progress"
+		}
+		p.InsertAfter(after, Stage{Name: "known-games", Run: knownGamesStage(opts)})
+	}
+	return p
+}
+
+// Use wraps every stage in m. Middleware added first ends up outermost.
+func (p *Pipeline) Use(m Middleware) {
+	p.middleware = append(p.middleware, m)
+}
+
+// InsertAfter adds s right after the stage called name.
+func (p *Pipeline) InsertAfter(name string, s Stage) error {
+	for i, st := range p.Stages {
+		if st.Name == name {
+			p.Stages = append(p.Stages[:i+1], append([]Stage{s}, p.Stages[i+1:]...)...)
+			return nil
+		}
+	}
+	return fmt.Errorf("pipeline has no stage %q", name)
+}
+
+// Run executes every stage, stopping at the first error.
+func (p *Pipeline) Run(r *Run) (err error) {
+	if r.Context == nil {
+		r.Context = context.Background()
+	}
+	ctx, span := tracer.Start(r.Context, "scrape")
+	span.SetAttributes(attribute.String("mslotto.run_id", r.ID))
+	defer func() { endSpan(span, err) }()
+
+	for _, s := range p.Stages {
+		for i := len(p.middleware) - 1; i >= 0; i-- {
+			s = p.middleware[i](s)
+		}
+		r.Context, r.stage = tracer.Start(ctx, s.Name)
+		err := s.Run(r)
+		if r.stage != nil {
+			endSpan(r.stage, err)
+		}
+		r.Context, r.stage = ctx, nil
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// discoverStage fetches the index and streams links straight from it, or
+// with Shuffle reads the whole index first and hands them out in random order so the same games
+// aren't always fetched last when a run is cut short. With Progress the
+// stalest games go first, shuffled among equally stale ones.
+func discoverStage(opts Options) func(*Run) error {
+	shuffle, progress := opts.Shuffle, opts.Progress
+	return func(r *Run) error {
+		page, err := scrape.GetHTML(opts.fetcher())
+		if err != nil {
+			return err
+		}
+		if !shuffle && progress == nil {
+			end := r.Detach()
+			links := scrape.StreamLinks(page)
+			out := make(chan string)
+			r.Links = out
+			go func() {
+				defer end()
+				defer close(out)
+				for l := range links {
+					out <- l
+				}
+			}()
+			return nil
+		}
+		links := scrape.GetLinks(page)
+		r.Index = append([]string(nil), links...)
+		if shuffle {
+			rand.Shuffle(len(links), func(i, j int) { links[i], links[j] = links[j], links[i] })
+		}
+		if progress != nil {
+			links = progress.Stalest(links)
+		}
+		ch := make(chan string, len(links))
+		for _, l := range links {
+			ch <- l
+		}
+		close(ch)
+		r.Links = ch
+		return nil
+	}
+}
+
+// fetchStage downloads game pages as discover streams their links in, and
+// hands them on to parse as they arrive. Once MaxDuration has passed,
+// remaining links are drained without being fetched. The stage returns
+// straight away; its span ends when the last download does.
+func fetchStage(opts Options) func(*Run) error {
+	concurrency, maxDuration := opts.Concurrency, opts.MaxDuration
+	return func(r *Run) error {
+		fetcher := opts.fetcher()
+		out := make(chan Page)
+		r.Fetched = out
+		ctx, end := r.Context, r.Detach()
+		go func() {
+			defer end()
+			defer close(out)
+			var wg sync.WaitGroup
+			var skipped int
+			var seen []string
+			streamed := r.Index == nil
+			sem := make(chan struct{}, concurrency)
+			for link := range r.Links {
+				if streamed {
+					seen = append(seen, link)
+				}
+				if maxDuration > 0 && time.Since(r.Started) > maxDuration {
+					skipped++
+					continue
+				}
+				sem <- struct{}{}
+				wg.Add(1)
+				go func(l string) {
+					defer func() { <-sem; wg.Done() }()
+					_, span := tracer.Start(ctx, "fetch page")
+					span.SetAttributes(attribute.String("url.full", l))
+					body, err := scrape.GamePage(fetcher, l)
+					endSpan(span, err)
+					if err != nil {
+						fmt.Println("Error fetching game page:", l, err)
+						r.Digest.Add(notify.Event{Severity: notify.Warning, Title: "Fetch failed", Message: fmt.Sprintf("%s: %v", l, err)})
+						return
+					}
+					out <- Page{URL: l, Body: body}
+				}(link)
+			}
+			wg.Wait()
+			if streamed {
+				r.Index = seen
+			}
+			if skipped > 0 {
+				r.logger().Printf("Time limit of %s reached, %d game page(s) left for the next run", maxDuration, skipped)
+			}
+		}()
+		return nil
+	}
+}
+
+// parseStage builds a game from each page as fetch delivers it, reusing
+// the last run's game for a page Known has seen unchanged.
+func parseStage(opts Options) func(*Run) error {
+	return func(r *Run) error {
+		var games []model.Game
+		var reused int
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, opts.Concurrency)
+		for p := range r.Fetched {
+			r.Pages = append(r.Pages, p)
+			sem <- struct{}{}
+			wg.Add(1)
+			go func() {
+				defer func() { <-sem; wg.Done() }()
+				_, span := tracer.Start(r.Context, "parse page")
+				span.SetAttributes(attribute.String("url.full", p.URL))
+				g, unchanged := model.Game{}, false
+				if opts.Known != nil {
+					g, unchanged = opts.Known.Unchanged(p)
+				}
+				if !unchanged {
+					g = parsePage(r, p, opts)
+				}
+				g.Settings = opts.Settings
+				span.End()
+				mu.Lock()
+				defer mu.Unlock()
+				games = append(games, g)
+				if unchanged {
+					reused++
+				}
+				if opts.OnGame != nil {
+					if opts.Known != nil {
+						g.New = opts.Known.IsNew(g)
+					}
+					opts.OnGame(g)
+				}
+			}()
+		}
+		wg.Wait()
+		r.Games, r.Reused = games, reused
+		if reused > 0 {
+			r.logger().Printf("%d game page(s) unchanged since the last run, kept what they parsed to then", reused)
+		}
+		return nil
+	}
+}
+
+// parsePage builds a game from a fetched page.
+func parsePage(r *Run, p Page, opts Options) model.Game {
+	g := scrape.BuildGame(scrape.ExtractTables(p.Body), scrape.ExtractGameName(p.URL), p.URL)
+	if g.LastUpdated.IsZero() {
+		g.LastUpdated = scrape.LastUpdated(p.Body)
+	}
+	if odds, ok := opts.LastOdds[g.GameNumber]; ok && g.Odds == 0 {
+		g.Odds = odds
+		r.logger().Printf("Odds missing for %s, carried forward 1:%.2f from the last run", g.Name, odds)
+		r.Digest.Add(notify.Event{Severity: notify.Warning, Title: "Odds carried forward",
+			Message: fmt.Sprintf("%s: the page shows no overall odds; using 1:%.2f from the last run that read them", g.Name, odds)})
+	}
+	if opts.PDF {
+		if err := scrape.EnrichFromPDF(opts.fetcher(), &g, p.Body); err != nil {
+			r.logger().Println("Error reading game sheet:", p.URL, err)
+		}
+	}
+	return g
+}
+
+// progressStage records what this run scraped and adds the games it didn't
+// reach from their last scrape, passing those on to OnGame too.
+func progressStage(opts Options) func(*Run) error {
+	return func(r *Run) error {
+		fresh := len(r.Games)
+		r.Games = opts.Progress.Carry(r.Index, r.Games, time.Now())
+		model.ApplySettings(r.Games, opts.Settings)
+		if opts.OnGame != nil {
+			for _, g := range r.Games[fresh:] {
+				if opts.Known != nil {
+					g.New = opts.Known.IsNew(g)
+				}
+				opts.OnGame(g)
+			}
+		}
+		return opts.Progress.Save()
+	}
+}
+
+// knownGamesStage flags the games no earlier run has seen and archives the
+// ones no longer on the index.
+func knownGamesStage(opts Options) func(*Run) error {
+	return func(r *Run) error {
+		added, ended := opts.Known.Observe(r.Games, r.Index, time.Now())
+		opts.Known.RecordPages(r.Pages)
+		model.ApplySettings(ended, opts.Settings)
+		r.Ended = ended
+		for _, g := range ended {
+			r.logger().Printf("Game ended: %s (#%d, $%d)", g.Name, g.GameNumber, g.Price)
+			r.Digest.Add(notify.Event{Severity: notify.Info, Title: "Game ended: " + g.Name,
+				Message: fmt.Sprintf("%s (game %d, $%d) is no longer on the active list; %d of %d prizes were left.", g.Name, g.GameNumber, g.Price, g.TotalRemainingPrizes, g.TotalOriginalPrizes)})
+		}
+		for _, g := range added {
+			r.logger().Printf("New game: %s (#%d, $%d)", g.Name, g.GameNumber, g.Price)
+			r.Digest.Add(notify.Event{Severity: notify.Info, Title: "New game: " + g.Name,
+				Message: fmt.Sprintf("%s (game %d, $%d) launched; EV %.2f. %s", g.Name, g.GameNumber, g.Price, g.ReportedEV(), g.URL)})
+			if opts.OnNewGame != nil {
+				opts.OnNewGame(g)
+			}
+		}
+		return opts.Known.Save()
+	}
+}
+
+// staleSiteData is how old the site's own "last updated" date can get before
+// a game's prize counts are flagged as not reflecting current sales.
+const staleSiteData = 7 * 24 * time.Hour
+
+func analyzeStage(r *Run) error {
+	r.Peers = analyze.NewPricePeers(r.Games, r.History)
+	for _, g := range r.Games {
+		if err := model.CheckPrice(g); err != nil {
+			r.logger().Println("Warning:", err)
+			r.Digest.Add(notify.Event{Severity: notify.Warning, Title: "Suspicious price", Message: err.Error()})
+		}
+		if !g.LastUpdated.IsZero() && time.Since(g.LastUpdated) > staleSiteData {
+			r.Digest.Add(notify.Event{Severity: notify.Warning, Title: "Stale prize data",
+				Message: fmt.Sprintf("%s: the site last updated its prize counts on %s", g.Name, g.LastUpdated.Format("2006-01-02"))})
+		}
+	}
+	if len(r.Games) == 0 {
+		r.Digest.Add(notify.Event{Severity: notify.Critical, Title: "No games scraped", Message: "the index returned no parsable games from " + scrape.StartURL})
+	}
+	analyze.SortByEV(r.Games, true)
+	title, summary := notify.RunSummary(r.Games, r.Peers, 5)
+	r.Digest.Add(notify.Event{Severity: notify.Info, Title: title, Message: summary})
+	return nil
+}
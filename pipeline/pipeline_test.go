@@ -0,0 +1,290 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"msLotto/model"
+	"msLotto/mslottotest"
+	"msLotto/notify"
+	"msLotto/scrape"
+)
+
+// syntheticServer serves n made-up game pages, slugs game-0 to game-(n-1),
+// and points the scraper at it for the rest of the test.
+func syntheticServer(t *testing.T, n int) *mslottotest.Server {
+	t.Helper()
+	r := rand.New(rand.NewPCG(1, 2))
+	pages := make([]mslottotest.Page, n)
+	for i := range pages {
+		pages[i] = mslottotest.Page{Slug: fmt.Sprintf("game-%d", i), Body: mslottotest.SyntheticPage(i, 4, r)}
+	}
+	srv := mslottotest.NewServer(pages...)
+	t.Cleanup(srv.Close)
+	t.Cleanup(srv.Install())
+	return srv
+}
+
+// index lists the pages numbered nums the way the site's index does.
+func index(nums ...int) []byte {
+	var b strings.Builder
+	for _, n := range nums {
+		fmt.Fprintf(&b, "<div class=\"col-lg-3 gamebox\"><a href=\"https://www.mslottery.com/games/game-%d/\">Game %d</a></div>\n", n, n)
+	}
+	return []byte(b.String())
+}
+
+// scrapeOnce runs the standard pipeline and returns the games written.
+func scrapeOnce(t *testing.T, opts Options) []model.Game {
+	t.Helper()
+	var games []model.Game
+	p := New(opts, func(r *Run) error {
+		games = r.Games
+		return nil
+	})
+	if err := p.Run(&Run{Started: time.Now(), Digest: &notify.Digest{}}); err != nil {
+		t.Fatal(err)
+	}
+	return games
+}
+
+func gameNumbers(games []model.Game) []int {
+	var nums []int
+	for _, g := range games {
+		nums = append(nums, g.GameNumber)
+	}
+	slices.Sort(nums)
+	return nums
+}
+
+func TestRun(t *testing.T) {
+	syntheticServer(t, 5)
+	for _, shuffle := range []bool{false, true} {
+		t.Run(fmt.Sprintf("shuffle=%v", shuffle), func(t *testing.T) {
+			var streamed int
+			games := scrapeOnce(t, Options{Concurrency: 3, Shuffle: shuffle, OnGame: func(model.Game) { streamed++ }})
+			if want := []int{1000, 1001, 1002, 1003, 1004}; !slices.Equal(gameNumbers(games), want) {
+				t.Errorf("scraped games %v, want %v", gameNumbers(games), want)
+			}
+			if streamed != len(games) {
+				t.Errorf("OnGame saw %d games, want %d", streamed, len(games))
+			}
+			for _, g := range games {
+				if g.Price <= 0 || len(g.PrizeTiers) != 4 || g.ParserVersion != scrape.ParserVersion {
+					t.Errorf("game %d parsed as %+v", g.GameNumber, g)
+				}
+			}
+		})
+	}
+}
+
+func TestRunKnownGames(t *testing.T) {
+	srv := syntheticServer(t, 4)
+	known, err := LoadKnownGames(filepath.Join(t.TempDir(), "known.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var added, streamedNew []int
+	opts := Options{Concurrency: 2, Known: known, OnNewGame: func(g model.Game) { added = append(added, g.GameNumber) }, OnGame: func(g model.Game) {
+		if g.New {
+			streamedNew = append(streamedNew, g.GameNumber)
+		}
+	}}
+
+	// Nothing is new on the first run.
+	srv.Index = index(0, 1, 2)
+	for _, g := range scrapeOnce(t, opts) {
+		if g.New {
+			t.Errorf("game %d flagged new on the first run", g.GameNumber)
+		}
+	}
+
+	// game-3 launches and game-0 leaves the index.
+	srv.Index = index(1, 2, 3)
+	games := scrapeOnce(t, opts)
+	for _, g := range games {
+		if g.New != (g.GameNumber == 1003) {
+			t.Errorf("game %d: New = %v", g.GameNumber, g.New)
+		}
+	}
+	if !slices.Equal(added, []int{1003}) {
+		t.Errorf("OnNewGame saw %v, want [1003]", added)
+	}
+	if !slices.Equal(streamedNew, []int{1003}) {
+		t.Errorf("OnGame saw %v flagged new, want [1003]", streamedNew)
+	}
+	ended := known.Ended()
+	if len(ended) != 1 || ended[0].GameNumber != 1000 || ended[0].Ended.IsZero() {
+		t.Errorf("Ended = %+v, want game 1000", ended)
+	}
+
+	// The file carries what was seen into the next run.
+	reloaded, err := LoadKnownGames(known.path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded.Games) != 4 || len(reloaded.Ended()) != 1 {
+		t.Errorf("reloaded %d known games, %d ended; want 4, 1", len(reloaded.Games), len(reloaded.Ended()))
+	}
+}
+
+func TestRunReusesUnchangedPages(t *testing.T) {
+	syntheticServer(t, 2)
+	known, err := LoadKnownGames(filepath.Join(t.TempDir(), "known.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := Options{Concurrency: 2, Known: known}
+	scrapeOnce(t, opts)
+
+	// Tag what the first run parsed, so a reused game is told apart from
+	// a reparsed one. Game 1001's hash no longer matches its page.
+	for key, seen := range known.Games {
+		seen.Game.Name = "Reused"
+		if seen.Game.GameNumber == 1001 {
+			seen.PageHash = "stale"
+		}
+		known.Games[key] = seen
+	}
+	for _, g := range scrapeOnce(t, opts) {
+		if reused := g.Name == "Reused"; reused != (g.GameNumber == 1000) {
+			t.Errorf("game %d named %q, want only game 1000 reused", g.GameNumber, g.Name)
+		}
+	}
+}
+
+func TestRunStreamsCarriedGames(t *testing.T) {
+	syntheticServer(t, 3)
+	progress, err := LoadProgress(filepath.Join(t.TempDir(), "progress.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scrapeOnce(t, Options{Concurrency: 2, Progress: progress})
+
+	// A run out of time before its first fetch reports every game from the
+	// last scrape, and streams them too.
+	var streamed []model.Game
+	games := scrapeOnce(t, Options{Concurrency: 2, Progress: progress, MaxDuration: time.Nanosecond, OnGame: func(g model.Game) { streamed = append(streamed, g) }})
+	if want := []int{1000, 1001, 1002}; !slices.Equal(gameNumbers(games), want) || !slices.Equal(gameNumbers(streamed), want) {
+		t.Errorf("carried %v and streamed %v, want %v", gameNumbers(games), gameNumbers(streamed), want)
+	}
+}
+
+func TestRunCarriesOddsForward(t *testing.T) {
+	// game-1's page has lost its odds row.
+	r := rand.New(rand.NewPCG(1, 2))
+	odds := regexp.MustCompile(`<tr><td>Overall Odds</td><td>[^<]*</td></tr>\n`)
+	srv := mslottotest.NewServer(
+		mslottotest.Page{Slug: "game-0", Body: mslottotest.SyntheticPage(0, 4, r)},
+		mslottotest.Page{Slug: "game-1", Body: odds.ReplaceAll(mslottotest.SyntheticPage(1, 4, r), nil)},
+	)
+	t.Cleanup(srv.Close)
+	t.Cleanup(srv.Install())
+
+	digest := &notify.Digest{}
+	var games []model.Game
+	p := New(Options{Concurrency: 2, LastOdds: map[int]float64{1000: 9, 1001: 4.25}}, func(r *Run) error {
+		games = r.Games
+		return nil
+	})
+	if err := p.Run(&Run{Started: time.Now(), Digest: digest}); err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range games {
+		if g.GameNumber == 1001 && g.Odds != 4.25 {
+			t.Errorf("game 1001 odds = %v, want 4.25 carried forward", g.Odds)
+		}
+		if g.GameNumber == 1000 && g.Odds == 9 {
+			t.Errorf("game 1000 took the recorded odds over its page's")
+		}
+	}
+	var carried int
+	for _, e := range digest.Events() {
+		if e.Title == "Odds carried forward" {
+			carried++
+		}
+	}
+	if carried != 1 {
+		t.Errorf("digest reported %d carried-forward odds, want 1", carried)
+	}
+}
+
+func TestRunTracing(t *testing.T) {
+	syntheticServer(t, 3)
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	scrapeOnce(t, Options{Concurrency: 2})
+
+	spans := map[string][]sdktrace.ReadOnlySpan{}
+	for _, s := range rec.Ended() {
+		spans[s.Name()] = append(spans[s.Name()], s)
+	}
+	fetch, parse := spans["fetch"][0], spans["parse"][0]
+	for name, stage := range map[string]sdktrace.ReadOnlySpan{"fetch page": fetch, "parse page": parse} {
+		if len(spans[name]) != 3 {
+			t.Fatalf("recorded %d %q spans, want 3", len(spans[name]), name)
+		}
+		for _, s := range spans[name] {
+			if s.Parent().SpanID() != stage.SpanContext().SpanID() {
+				t.Errorf("%q span isn't a child of its stage", name)
+			}
+			if s.EndTime().After(stage.EndTime()) {
+				t.Errorf("%q span ends after its stage", name)
+			}
+		}
+	}
+	if discover := spans["discover"][0]; discover.Parent().SpanID() != spans["scrape"][0].SpanContext().SpanID() {
+		t.Error("discover span isn't a child of the run")
+	}
+}
+
+func TestPipelineStages(t *testing.T) {
+	var ran []string
+	stage := func(name string) Stage {
+		return Stage{Name: name, Run: func(*Run) error { ran = append(ran, name); return nil }}
+	}
+	p := &Pipeline{Stages: []Stage{stage("a"), stage("c")}}
+	if err := p.InsertAfter("a", stage("b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.InsertAfter("missing", stage("x")); err == nil {
+		t.Error("InsertAfter found a stage that doesn't exist")
+	}
+	for _, tag := range []string{"outer", "inner"} {
+		p.Use(func(next Stage) Stage {
+			return Stage{Name: next.Name, Run: func(r *Run) error {
+				ran = append(ran, tag+" "+next.Name)
+				return next.Run(r)
+			}}
+		})
+	}
+	if err := p.Run(&Run{}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"outer a", "inner a", "a", "outer b", "inner b", "b", "outer c", "inner c", "c"}
+	if !slices.Equal(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	// The first failing stage stops the run and names itself.
+	boom := errors.New("boom")
+	p = &Pipeline{Stages: []Stage{{Name: "fail", Run: func(*Run) error { return boom }}, stage("never")}}
+	ran = nil
+	if err := p.Run(&Run{}); !errors.Is(err, boom) || !strings.HasPrefix(err.Error(), "fail: ") {
+		t.Errorf("Run = %v, want fail: boom", err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("stages after a failure ran: %v", ran)
+	}
+}
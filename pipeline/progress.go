@@ -0,0 +1,90 @@
+package pipeline
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"time"
+
+	"msLotto/model"
+)
+
+// Progress remembers when each game was last scraped and what it parsed to,
+// so a time-boxed run can spend its window on the stalest games and still
+// report every game on the index.
+type Progress struct {
+	Games map[string]ProgressEntry `json:"games"` // by game URL
+
+	path string
+}
+
+// ProgressEntry is the last successful scrape of one game.
+type ProgressEntry struct {
+	Scraped time.Time  `json:"scraped"`
+	Game    model.Game `json:"game"`
+}
+
+// LoadProgress reads the progress file at path. A missing file is a fresh
+// start where every game counts as stale.
+func LoadProgress(path string) (*Progress, error) {
+	p := &Progress{Games: map[string]ProgressEntry{}, path: path}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, err
+	}
+	if p.Games == nil {
+		p.Games = map[string]ProgressEntry{}
+	}
+	return p, nil
+}
+
+// Save writes the progress back to the file it was loaded from.
+func (p *Progress) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(p.path, append(data, '\n'), 0o644)
+}
+
+// Stalest orders links so games never scraped come first, then the ones
+// scraped longest ago. The sort is stable, so ties keep their given order.
+func (p *Progress) Stalest(links []string) []string {
+	out := append([]string(nil), links...)
+	sort.SliceStable(out, func(i, j int) bool {
+		return p.Games[out[i]].Scraped.Before(p.Games[out[j]].Scraped)
+	})
+	return out
+}
+
+// Carry records the games scraped this run, forgets games no longer on the
+// index, and returns the fresh games followed by the remembered ones that
+// weren't reached this time.
+func (p *Progress) Carry(index []string, fresh []model.Game, now time.Time) []model.Game {
+	scraped := map[string]bool{}
+	for _, g := range fresh {
+		p.Games[g.URL] = ProgressEntry{Scraped: now, Game: g}
+		scraped[g.URL] = true
+	}
+
+	active := map[string]bool{}
+	games := fresh
+	for _, l := range index {
+		active[l] = true
+		if e, ok := p.Games[l]; ok && !scraped[l] {
+			games = append(games, e.Game)
+		}
+	}
+	for url := range p.Games {
+		if !active[url] {
+			delete(p.Games, url)
+		}
+	}
+	return games
+}
@@ -0,0 +1,87 @@
+package pipeline
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RunRecord is one line of the run log.
+type RunRecord struct {
+	Started  time.Time `json:"started"`
+	Duration float64   `json:"duration_seconds"`
+	Requests int64     `json:"requests"`
+	Errors   int64     `json:"errors"`
+	Games    int       `json:"games"`
+}
+
+// ErrorRate is the share of requests that failed.
+func (r RunRecord) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// ReadRunLog loads every record from a JSON-lines run log. A missing file is
+// an empty history.
+func ReadRunLog(path string) ([]RunRecord, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var runs []RunRecord
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r RunRecord
+		if err := json.Unmarshal(sc.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		runs = append(runs, r)
+	}
+	return runs, sc.Err()
+}
+
+// AppendRunLog adds one record to the end of the run log.
+func AppendRunLog(path string, r RunRecord) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// SlowRun compares a run against the rolling average of the last window runs
+// and describes the slowdown when it took more than thresholdPct longer.
+// It returns "" when the run is within bounds or there is no history yet.
+func SlowRun(history []RunRecord, r RunRecord, window int, thresholdPct float64) string {
+	if len(history) > window {
+		history = history[len(history)-window:]
+	}
+	if len(history) == 0 {
+		return ""
+	}
+	var sum float64
+	for _, h := range history {
+		sum += h.Duration
+	}
+	avg := sum / float64(len(history))
+	if avg == 0 || r.Duration <= avg*(1+thresholdPct/100) {
+		return ""
+	}
+	return fmt.Sprintf("run took %.1fs, %.0f%% longer than the %.1fs average of the last %d runs (%.1f%% of requests failed)",
+		r.Duration, (r.Duration/avg-1)*100, avg, len(history), r.ErrorRate()*100)
+}
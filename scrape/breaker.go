@@ -0,0 +1,64 @@
+package scrape
+
+import (
+	"sync"
+	"time"
+)
+
+// CircuitBreaker stops hammering a site that is down or blocking us. After
+// Threshold fetches fail in a row it trips: every fetch waits out Cooldown
+// before going to the site again, and one more failure after that trips it
+// again straight away.
+type CircuitBreaker struct {
+	Next      Fetcher
+	Threshold int
+	Cooldown  time.Duration
+	// OnTrip, if set, is called each time the breaker trips, with the
+	// failure that tripped it.
+	OnTrip func(err error)
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	trips     int
+}
+
+func (b *CircuitBreaker) Fetch(url string) ([]byte, error) {
+	b.mu.Lock()
+	for time.Now().Before(b.openUntil) {
+		wait := time.Until(b.openUntil)
+		b.mu.Unlock()
+		time.Sleep(wait)
+		b.mu.Lock()
+	}
+	b.mu.Unlock()
+
+	body, err := b.Next.Fetch(url)
+
+	b.mu.Lock()
+	if err == nil {
+		b.failures = 0
+		b.mu.Unlock()
+		return body, nil
+	}
+	b.failures++
+	// Fetches already in flight when it tripped don't trip it again.
+	tripped := b.failures >= b.Threshold && !time.Now().Before(b.openUntil)
+	if tripped {
+		b.openUntil = time.Now().Add(b.Cooldown)
+		b.failures = b.Threshold - 1
+		b.trips++
+	}
+	b.mu.Unlock()
+	if tripped && b.OnTrip != nil {
+		b.OnTrip(err)
+	}
+	return body, err
+}
+
+// Trips is how many times the breaker has tripped.
+func (b *CircuitBreaker) Trips() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.trips
+}
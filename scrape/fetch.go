@@ -0,0 +1,110 @@
+// Package scrape fetches mslottery.com game pages and parses them into
+// model.Games.
+package scrape
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// StartURL is the index of active games that discovery starts from.
+var StartURL = "https://www.mslottery.com/gamestatus/active/"
+
+// Fetcher retrieves the raw body of a page.
+type Fetcher interface {
+	Fetch(url string) ([]byte, error)
+}
+
+// HTTPFetcher is the default Fetcher, a plain GET.
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+func (f HTTPFetcher) Fetch(url string) ([]byte, error) {
+	resp, err := f.Client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// FallbackFetcher uses Primary and only retries with Fallback when the
+// primary body fails the Usable check, e.g. tables rendered by JavaScript.
+type FallbackFetcher struct {
+	Primary  Fetcher
+	Fallback Fetcher
+	Usable   func([]byte) bool
+}
+
+func (f FallbackFetcher) Fetch(url string) ([]byte, error) {
+	body, err := f.Primary.Fetch(url)
+	if err == nil && f.Usable(body) {
+		return body, nil
+	}
+	return f.Fallback.Fetch(url)
+}
+
+// CountingFetcher records how much traffic a run sends to the site.
+type CountingFetcher struct {
+	Next Fetcher
+
+	requests atomic.Int64
+	errors   atomic.Int64
+	bytes    atomic.Int64
+	elapsed  atomic.Int64 // nanoseconds spent waiting on responses
+}
+
+func (f *CountingFetcher) Fetch(url string) ([]byte, error) {
+	start := time.Now()
+	body, err := f.Next.Fetch(url)
+	f.elapsed.Add(int64(time.Since(start)))
+	f.requests.Add(1)
+	if err != nil {
+		f.errors.Add(1)
+	}
+	f.bytes.Add(int64(len(body)))
+	return body, err
+}
+
+// Requests is the number of fetches made so far.
+func (f *CountingFetcher) Requests() int64 { return f.requests.Load() }
+
+// Errors is the number of fetches that failed.
+func (f *CountingFetcher) Errors() int64 { return f.errors.Load() }
+
+// Bytes is the size of every body downloaded so far.
+func (f *CountingFetcher) Bytes() int64 { return f.bytes.Load() }
+
+// Waited is the time spent waiting on responses so far, over all fetches.
+func (f *CountingFetcher) Waited() time.Duration { return time.Duration(f.elapsed.Load()) }
+
+// Summary is a one-line report of the traffic seen so far.
+func (f *CountingFetcher) Summary() string {
+	n := f.requests.Load()
+	var avg time.Duration
+	if n > 0 {
+		avg = time.Duration(f.elapsed.Load() / n)
+	}
+	return fmt.Sprintf("%d requests, %.1f KB downloaded, %s average response time",
+		n, float64(f.bytes.Load())/1024, avg.Round(time.Millisecond))
+}
+
+// looksRendered reports whether a page already carries the markup we parse.
+func looksRendered(body []byte) bool {
+	return bytes.Contains(body, []byte("<table")) || bytes.Contains(body, []byte("gamebox"))
+}
+
+// DefaultFetcher is what a pipeline fetches through when its options don't
+// name a Fetcher.
+var DefaultFetcher Fetcher = HTTPFetcher{Client: http.DefaultClient}
+
+// HeadlessFallback wraps the HTTP fetcher when built with -tags chromedp.
+var HeadlessFallback func(Fetcher) Fetcher
@@ -0,0 +1,45 @@
+//go:build chromedp
+
+package scrape
+
+import (
+	"context"
+	"time"
+
+	"github.com/chromedp/chromedp"
+)
+
+// Built with -tags chromedp, pages that come back without their tables are
+// re-fetched through headless Chrome so JavaScript-rendered content still parses.
+func init() {
+	HeadlessFallback = func(primary Fetcher) Fetcher {
+		return FallbackFetcher{
+			Primary:  primary,
+			Fallback: ChromeFetcher{Timeout: 30 * time.Second},
+			Usable:   looksRendered,
+		}
+	}
+}
+
+// ChromeFetcher renders the page in headless Chrome and returns the final DOM.
+type ChromeFetcher struct {
+	Timeout time.Duration
+}
+
+func (f ChromeFetcher) Fetch(url string) ([]byte, error) {
+	ctx, cancel := chromedp.NewContext(context.Background())
+	defer cancel()
+	ctx, cancel = context.WithTimeout(ctx, f.Timeout)
+	defer cancel()
+
+	var out string
+	err := chromedp.Run(ctx,
+		chromedp.Navigate(url),
+		chromedp.WaitReady("body"),
+		chromedp.OuterHTML("html", &out),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out), nil
+}
@@ -0,0 +1,56 @@
+package scrape
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// NetOptions control how the HTTP client reaches the lottery site when the
+// default network path misbehaves.
+type NetOptions struct {
+	IPVersion int               // 4 or 6 to force a stack, 0 for either
+	Resolve   map[string]string // fixed host -> IP mapping, like curl --resolve
+	DNSServer string            // "host:port" of a resolver to use instead of the system one
+}
+
+// NewHTTPClient builds a client that dials according to opts.
+func NewHTTPClient(opts NetOptions) (*http.Client, error) {
+	network := "tcp"
+	switch opts.IPVersion {
+	case 0:
+	case 4:
+		network = "tcp4"
+	case 6:
+		network = "tcp6"
+	default:
+		return nil, fmt.Errorf("IP version must be 4 or 6, got %d", opts.IPVersion)
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if opts.DNSServer != "" {
+		server := opts.DNSServer
+		dialer.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, netw, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, netw, server)
+			},
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		if ip, ok := opts.Resolve[host]; ok {
+			addr = net.JoinHostPort(ip, port)
+		}
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return &http.Client{Transport: transport}, nil
+}
@@ -0,0 +1,296 @@
+package scrape
+
+import (
+	"bytes"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+
+	"msLotto/model"
+)
+
+// ParserVersion identifies how BuildGame reads a page. Bump it with any
+// change that alters what is read from the same page, so history recorded by
+// an older parser can be told apart.
+//
+//	1: first recorded version
+//	2: free-ticket prizes are valued at the ticket price instead of $0
+const ParserVersion = 2
+
+// BadParserVersions lists parser versions since found to misread pages. The
+// history database leaves their rows out of every analysis; reparse the
+// pages kept with -keep-artifacts to recover those runs.
+var BadParserVersions []int
+
+// GameMeta holds the fields read from a game's details table.
+type GameMeta struct {
+	Price        int
+	Odds         float64
+	LaunchDate   string
+	GameNumber   int
+	TotalTickets int
+	PackSize     int
+	LastUpdated  time.Time
+}
+
+func ParseMetaData(table [][]string) GameMeta {
+	var meta GameMeta
+	for _, row := range table {
+		row = nonEmpty(row)
+		if len(row) < 2 {
+			continue
+		}
+		key := strings.ToLower(row[0])
+		val := row[1]
+
+		switch {
+		case strings.Contains(key, "ticket price"):
+			meta.Price = parseDollar(val)
+		case strings.Contains(key, "overall odds"):
+			meta.Odds = parseOdds(val)
+		case strings.Contains(key, "launch date"):
+			meta.LaunchDate = val
+		case strings.Contains(key, "game number"), strings.Contains(key, "game #"), strings.Contains(key, "game no"):
+			meta.GameNumber = parseInt(strings.TrimPrefix(strings.TrimSpace(val), "#"))
+		// Before the ticket count, which "number of tickets per pack" would match.
+		case strings.Contains(key, "pack"), strings.Contains(key, "per book"), strings.Contains(key, "book size"):
+			meta.PackSize = parseCount(val)
+		case strings.Contains(key, "number of tickets"), strings.Contains(key, "tickets printed"):
+			meta.TotalTickets = parseCount(val)
+		case strings.Contains(key, "last updated"), strings.Contains(key, "as of"):
+			meta.LastUpdated = parseDate(val)
+		}
+	}
+	return meta
+}
+
+// prizeColumns records where each field sits in a prize table row.
+type prizeColumns struct {
+	value, original, remaining, odds int
+	claimed                          int // closed games list prizes paid instead of the original count
+}
+
+// mapPrizeColumns reads the header row so columns can come in any order and
+// extra ones ("% Remaining", "Odds") can sit between them. Headers it can't
+// place fall back to the classic value, original, remaining layout.
+func mapPrizeColumns(header []string) prizeColumns {
+	cols := prizeColumns{value: -1, original: -1, remaining: -1, odds: -1, claimed: -1}
+	for i, h := range header {
+		h = strings.ToLower(h)
+		switch {
+		case strings.Contains(h, "%"), strings.Contains(h, "percent"):
+			// derived from the counts, nothing to keep
+		case strings.Contains(h, "odds"):
+			cols.odds = i
+		case strings.Contains(h, "remain"), strings.Contains(h, "unclaimed"):
+			cols.remaining = i
+		case strings.Contains(h, "claimed"), strings.Contains(h, "paid"):
+			cols.claimed = i
+		case strings.Contains(h, "original"), strings.Contains(h, "total"), strings.Contains(h, "start"), strings.Contains(h, "printed"):
+			cols.original = i
+		case strings.Contains(h, "prize"), strings.Contains(h, "amount"), strings.Contains(h, "value"):
+			cols.value = i
+		}
+	}
+	if cols.value < 0 || cols.remaining < 0 || (cols.original < 0 && cols.claimed < 0) {
+		return prizeColumns{value: 0, original: 1, remaining: 2, odds: cols.odds, claimed: -1}
+	}
+	return cols
+}
+
+func nonEmpty(row []string) []string {
+	var out []string
+	for _, c := range row {
+		if c != "" {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func ParsePrizes(table [][]string) []model.PrizeTier {
+	var prizes []model.PrizeTier
+	if len(table) == 0 {
+		return prizes
+	}
+
+	cols := mapPrizeColumns(table[0])
+	width := max(cols.value, cols.original, cols.remaining, cols.claimed) + 1
+
+	for _, row := range table[1:] { // Skip header row
+		if len(row) < width {
+			continue
+		}
+
+		if strings.Contains(strings.ToLower(row[cols.value]), "2nd chance") {
+			continue
+		}
+
+		value, tag := parsePrizeCell(row[cols.value])
+		remain := parseInt(row[cols.remaining])
+		var orig int
+		if cols.original >= 0 {
+			orig = parseInt(row[cols.original])
+		} else {
+			// "Prizes Paid / Unclaimed" tables on ended games
+			orig = parseInt(row[cols.claimed]) + remain
+		}
+
+		tier := model.PrizeTier{
+			Value:          value,
+			OriginalCount:  orig,
+			RemainingCount: remain,
+			Tag:            tag,
+		}
+		if cols.odds >= 0 && cols.odds < len(row) {
+			tier.Odds = parseOdds(row[cols.odds])
+		}
+		prizes = append(prizes, tier)
+	}
+	return prizes
+}
+
+func parseDollar(s string) int {
+	s = strings.ReplaceAll(s, "$", "")
+	s = strings.ReplaceAll(s, ",", "")
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+var prizeAmount = regexp.MustCompile(`\$?\d[\d,]*`)
+
+// parsePrizeCell splits a prize cell such as "$500 WIN ALL" into its dollar
+// amount and whatever label the site printed around it. Bonus tiers are listed
+// as their own rows and would otherwise parse to a $0 prize.
+func parsePrizeCell(s string) (value int, tag string) {
+	loc := prizeAmount.FindStringIndex(s)
+	if loc == nil {
+		return 0, strings.ToUpper(strings.TrimSpace(s))
+	}
+	value = parseDollar(s[loc[0]:loc[1]])
+	tag = strings.Join(strings.Fields(s[:loc[0]]+" "+s[loc[1]:]), " ")
+	return value, strings.ToUpper(tag)
+}
+
+func parseOdds(s string) float64 {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return 0.0
+	}
+	f, _ := strconv.ParseFloat(parts[1], 64)
+	return f
+}
+
+func parseInt(s string) int {
+	n, _ := strconv.Atoi(strings.ReplaceAll(s, ",", ""))
+	return n
+}
+
+var countPattern = regexp.MustCompile(`(?i)(\d[\d,]*(?:\.\d+)?)\s*(million)?`)
+
+// parseCount reads a ticket count the way the details table words it:
+// "Approximately 6,000,000", "6.2 million" or "150 tickets".
+func parseCount(s string) int {
+	m := countPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", ""), 64)
+	if m[2] != "" {
+		n *= 1e6
+	}
+	return int(math.Round(n))
+}
+
+// dateLayouts are the ways the site has printed dates.
+var dateLayouts = []string{
+	"1/2/2006 3:04 PM", "1/2/2006 3:04PM", "1/2/2006", "1/2/06",
+	"January 2, 2006", "Jan 2, 2006", "Jan. 2, 2006", "2006-01-02",
+}
+
+func parseDate(s string) time.Time {
+	s = strings.Join(strings.Fields(s), " ")
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var updatedPattern = regexp.MustCompile(`(?i)(?:last updated|updated|as of)\s*(?:on|:)?\s*(\d{1,2}/\d{1,2}/\d{2,4}(?: \d{1,2}:\d{2} ?[AP]M)?|[A-Z][a-z]{2,8}\.? \d{1,2}, \d{4}|\d{4}-\d{2}-\d{2})`)
+
+// LastUpdated finds the "last updated" date printed anywhere in a game page's
+// text, for pages that show it outside the details table.
+func LastUpdated(page []byte) time.Time {
+	var text []string
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			break
+		}
+		if tt == html.TextToken {
+			if t := strings.TrimSpace(string(z.Text())); t != "" {
+				text = append(text, t)
+			}
+		}
+	}
+	if m := updatedPattern.FindStringSubmatch(strings.Join(text, " ")); m != nil {
+		return parseDate(m[1])
+	}
+	return time.Time{}
+}
+
+// ExtractGameName derives a game's name from the last part of its URL.
+func ExtractGameName(url string) string {
+	parts := strings.Split(strings.Trim(url, "/"), "/")
+	if len(parts) > 1 {
+		return strings.ReplaceAll(parts[len(parts)-1], "-", " ")
+	}
+	return url
+}
+func BuildGame(tables []Table, name string, url string) model.Game {
+	// Pick tables by what they're labelled as, and only fall back to the
+	// classic order (details, then prizes) for pages without labels.
+	meta, ok := FindTable(tables, "game details", "game info", "details")
+	if !ok && len(tables) > 0 {
+		meta = tables[0]
+	}
+	prizeTable, ok := FindTable(tables, "prize")
+	if !ok && len(tables) > 1 {
+		prizeTable = tables[1]
+	}
+
+	m := ParseMetaData(meta.Rows)
+	prizeTiers := ParsePrizes(prizeTable.Rows)
+
+	var totalOrg, totalRemain int
+	for i, p := range prizeTiers {
+		if p.IsFreeTicket() && p.Value == 0 {
+			prizeTiers[i].Value = m.Price
+		}
+		totalOrg += p.OriginalCount
+		totalRemain += p.RemainingCount
+	}
+	game := model.Game{
+		Name:                 name,
+		Price:                m.Price,
+		Odds:                 m.Odds,
+		LaunchDate:           m.LaunchDate,
+		GameNumber:           m.GameNumber,
+		PrizeTiers:           prizeTiers,
+		TotalOriginalPrizes:  totalOrg,
+		TotalRemainingPrizes: totalRemain,
+		TotalTickets:         m.TotalTickets,
+		PackSize:             m.PackSize,
+		LastUpdated:          m.LastUpdated,
+		URL:                  url,
+		ParserVersion:        ParserVersion,
+	}
+	return game
+}
@@ -0,0 +1,181 @@
+package scrape
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"msLotto/model"
+)
+
+// buildFixture parses testdata/name as the game page at url.
+func buildFixture(t *testing.T, name, url string) model.Game {
+	t.Helper()
+	page, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := BuildGame(ExtractTables(page), ExtractGameName(url), url)
+	if g.LastUpdated.IsZero() {
+		g.LastUpdated = LastUpdated(page)
+	}
+	return g
+}
+
+func TestBuildGame(t *testing.T) {
+	tests := []struct {
+		fixture string
+		url     string
+		want    model.Game
+	}{
+		{
+			// Labelled tables, prize table first, extra columns and a
+			// free-ticket tier.
+			fixture: "labelled.html",
+			url:     "https://www.mslottery.com/games/lucky-7s/",
+			want: model.Game{
+				Name:       "lucky 7s",
+				Price:      2,
+				Odds:       4.12,
+				LaunchDate: "1/7/2025",
+				GameNumber: 737,
+				PrizeTiers: []model.PrizeTier{
+					{Value: 77777, OriginalCount: 5, RemainingCount: 2, Odds: 480000},
+					{Value: 500, OriginalCount: 100, RemainingCount: 55, Odds: 24000, Tag: "WIN ALL"},
+					{Value: 20, OriginalCount: 16000, RemainingCount: 8000, Odds: 150},
+					{Value: 2, OriginalCount: 240000, RemainingCount: 120000, Odds: 10, Tag: "FREE TICKET"},
+				},
+				TotalOriginalPrizes:  256105,
+				TotalRemainingPrizes: 128057,
+				TotalTickets:         2400000,
+				PackSize:             150,
+				LastUpdated:          time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC),
+				URL:                  "https://www.mslottery.com/games/lucky-7s/",
+				ParserVersion:        ParserVersion,
+			},
+		},
+		{
+			// No labels: details first, then prizes, with headers the
+			// parser doesn't recognize.
+			fixture: "classic.html",
+			url:     "https://www.mslottery.com/games/cash-blast/",
+			want: model.Game{
+				Name:       "cash blast",
+				Price:      5,
+				Odds:       3.5,
+				LaunchDate: "10/1/2024",
+				GameNumber: 612,
+				PrizeTiers: []model.PrizeTier{
+					{Value: 100000, OriginalCount: 4, RemainingCount: 1},
+					{Value: 5, OriginalCount: 300000, RemainingCount: 120000},
+				},
+				TotalOriginalPrizes:  300004,
+				TotalRemainingPrizes: 120001,
+				URL:                  "https://www.mslottery.com/games/cash-blast/",
+				ParserVersion:        ParserVersion,
+			},
+		},
+		{
+			// An ended game lists prizes paid instead of the original count.
+			fixture: "ended.html",
+			url:     "https://www.mslottery.com/games/big-money/",
+			want: model.Game{
+				Name:       "big money",
+				Price:      10,
+				Odds:       3.2,
+				GameNumber: 501,
+				PrizeTiers: []model.PrizeTier{
+					{Value: 250000, OriginalCount: 4, RemainingCount: 1},
+					{Value: 10, OriginalCount: 200000, RemainingCount: 0},
+				},
+				TotalOriginalPrizes:  200004,
+				TotalRemainingPrizes: 1,
+				LastUpdated:          time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC),
+				URL:                  "https://www.mslottery.com/games/big-money/",
+				ParserVersion:        ParserVersion,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			got := buildFixture(t, tt.fixture, tt.url)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BuildGame =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePrizeCell(t *testing.T) {
+	tests := []struct {
+		cell  string
+		value int
+		tag   string
+	}{
+		{"$1,000", 1000, ""},
+		{"$500 WIN ALL", 500, "WIN ALL"},
+		{"Bonus $50", 50, "BONUS"},
+		{"Free Ticket", 0, "FREE TICKET"},
+		{"$25,000 Harley-Davidson", 25000, "HARLEY-DAVIDSON"},
+	}
+	for _, tt := range tests {
+		value, tag := parsePrizeCell(tt.cell)
+		if value != tt.value || tag != tt.tag {
+			t.Errorf("parsePrizeCell(%q) = %d, %q; want %d, %q", tt.cell, value, tag, tt.value, tt.tag)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"Approximately 6,000,000", 6000000},
+		{"6.2 million", 6200000},
+		{"150 tickets", 150},
+		{"n/a", 0},
+	}
+	for _, tt := range tests {
+		if got := parseCount(tt.s); got != tt.want {
+			t.Errorf("parseCount(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{"3/14/2025", time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"3/14/2025 4:30 PM", time.Date(2025, 3, 14, 16, 30, 0, 0, time.UTC)},
+		{"Mar. 14, 2025", time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"2025-03-14", time.Date(2025, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"soon", time.Time{}},
+	}
+	for _, tt := range tests {
+		if got := parseDate(tt.s); !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestMapPrizeColumns(t *testing.T) {
+	tests := []struct {
+		header []string
+		want   prizeColumns
+	}{
+		{[]string{"Prize Amount", "Total Prizes", "Prizes Remaining"}, prizeColumns{value: 0, original: 1, remaining: 2, odds: -1, claimed: -1}},
+		{[]string{"Prizes Remaining", "Odds", "Prize", "% Remaining", "Original"}, prizeColumns{value: 2, original: 4, remaining: 0, odds: 1, claimed: -1}},
+		{[]string{"Prize", "Prizes Paid", "Prizes Unclaimed"}, prizeColumns{value: 0, original: -1, remaining: 2, odds: -1, claimed: 1}},
+		{[]string{"", "", ""}, prizeColumns{value: 0, original: 1, remaining: 2, odds: -1, claimed: -1}},
+	}
+	for _, tt := range tests {
+		if got := mapPrizeColumns(tt.header); got != tt.want {
+			t.Errorf("mapPrizeColumns(%q) = %+v, want %+v", tt.header, got, tt.want)
+		}
+	}
+}
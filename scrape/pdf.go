@@ -0,0 +1,104 @@
+package scrape
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"golang.org/x/net/html"
+
+	"msLotto/model"
+)
+
+// FindPDFLinks returns the href of every PDF linked from a game page.
+func FindPDFLinks(page []byte) []string {
+	var links []string
+	z := html.NewTokenizer(bytes.NewReader(page))
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return links
+		}
+		if tt != html.StartTagToken {
+			continue
+		}
+		t := z.Token()
+		if t.Data != "a" {
+			continue
+		}
+		for _, a := range t.Attr {
+			if a.Key == "href" && strings.HasSuffix(strings.ToLower(a.Val), ".pdf") {
+				links = append(links, a.Val)
+			}
+		}
+	}
+}
+
+var (
+	upcPattern     = regexp.MustCompile(`(?i)UPC[#:\s]*([\d\s-]{8,})`)
+	printedPattern = regexp.MustCompile(`(?i)(?:tickets printed|total tickets|number of tickets)[^\d]{0,20}([\d,]+)`)
+)
+
+// ParseGameSheet pulls the fields only the printable game sheet carries out of
+// its plain text.
+func ParseGameSheet(text string) (upc string, totalTickets int) {
+	if m := upcPattern.FindStringSubmatch(text); m != nil {
+		upc = strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(m[1]))
+	}
+	if m := printedPattern.FindStringSubmatch(text); m != nil {
+		totalTickets = parseInt(m[1])
+	}
+	return upc, totalTickets
+}
+
+// pdfText extracts the plain text of every page in a PDF.
+func pdfText(data []byte) (string, error) {
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+	text, err := r.GetPlainText()
+	if err != nil {
+		return "", err
+	}
+	b, err := io.ReadAll(text)
+	return string(b), err
+}
+
+// EnrichFromPDF fetches the game sheets linked from page through f and fills
+// in UPC and the printed ticket count when the HTML didn't already provide
+// them.
+func EnrichFromPDF(f Fetcher, g *model.Game, page []byte) error {
+	base, err := url.Parse(g.URL)
+	if err != nil {
+		return err
+	}
+	for _, link := range FindPDFLinks(page) {
+		ref, err := url.Parse(link)
+		if err != nil {
+			continue
+		}
+		data, err := f.Fetch(base.ResolveReference(ref).String())
+		if err != nil {
+			return err
+		}
+		text, err := pdfText(data)
+		if err != nil {
+			return err
+		}
+		upc, printed := ParseGameSheet(text)
+		if g.UPC == "" {
+			g.UPC = upc
+		}
+		if g.TotalTickets == 0 {
+			g.TotalTickets = printed
+		}
+		if g.UPC != "" && g.TotalTickets != 0 {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package scrape
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// GetHTML fetches the index of active games through f.
+func GetHTML(f Fetcher) ([]byte, error) {
+	return f.Fetch(StartURL)
+}
+
+// GetLinks lists the game links on page, the index.
+func GetLinks(page []byte) []string {
+	var links []string
+	scanLinks(page, func(link string) { links = append(links, link) })
+	return links
+}
+
+// StreamLinks sends the game links on page, the index, as it is tokenized, so
+// page fetches can start before the whole index has been walked.
+func StreamLinks(page []byte) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		scanLinks(page, func(link string) { out <- link })
+	}()
+	return out
+}
+
+func scanLinks(page []byte, emit func(string)) {
+	z := html.NewTokenizer(bytes.NewReader(page))
+
+	inActiveSession := false
+	divDepth := 0
+
+	for {
+		tt := z.Next()
+
+		if tt == html.ErrorToken {
+			return
+		}
+
+		token := z.Token()
+
+		// Detect start of ACTIVE GAMES main div
+		if tt == html.StartTagToken && token.Data == "div" {
+			for _, a := range token.Attr {
+				if a.Key == "class" && a.Val == "col-lg-3 gamebox" {
+					inActiveSession = true
+					divDepth = 1
+					break
+				}
+			}
+
+			// If already inside session, count nested divs
+			if inActiveSession && !(token.Data == "div" && divDepth == 1) {
+				divDepth++
+			}
+		}
+
+		// Detect end tags
+		if tt == html.EndTagToken && token.Data == "div" {
+			if inActiveSession {
+				divDepth--
+				if divDepth == 0 {
+					inActiveSession = false
+				}
+			}
+		}
+
+		// Capture links inside the active session
+		if inActiveSession && tt == html.StartTagToken && token.Data == "a" {
+			for _, a := range token.Attr {
+				if a.Key == "href" {
+					emit(a.Val)
+				}
+			}
+		}
+	}
+}
+
+func GamePage(f Fetcher, url string) ([]byte, error) {
+	return f.Fetch(url)
+}
+
+// Table is one <table> from a page, labelled with its caption or, failing
+// that, the nearest heading before it.
+type Table struct {
+	Label string
+	Rows  [][]string
+}
+
+func ExtractTables(htmlBytes []byte) []Table {
+	z := html.NewTokenizer(bytes.NewReader(htmlBytes))
+
+	var tables []Table
+	var currentTable Table
+	var currentRow []string
+	var currentCell []string
+	var heading []string
+	var lastHeading string
+
+	inTable := false
+	inRow := false
+	inCell := false
+	inHeading := false
+	inCaption := false
+
+	for {
+		tt := z.Next()
+		switch tt {
+
+		case html.ErrorToken:
+			return tables
+
+		case html.StartTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "table":
+				inTable = true
+				currentTable = Table{Label: lastHeading, Rows: [][]string{}}
+
+			case "caption":
+				if inTable {
+					inCaption = true
+					heading = nil
+				}
+
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				if !inTable {
+					inHeading = true
+					heading = nil
+				}
+
+			case "tr":
+				if inTable {
+					inRow = true
+					currentRow = []string{}
+				}
+
+			case "td", "th":
+				if inRow {
+					inCell = true
+					currentCell = nil
+				}
+			}
+
+		case html.EndTagToken:
+			t := z.Token()
+			switch t.Data {
+			case "caption":
+				if inCaption {
+					inCaption = false
+					currentTable.Label = strings.Join(heading, " ")
+				}
+
+			case "h1", "h2", "h3", "h4", "h5", "h6":
+				if inHeading {
+					inHeading = false
+					lastHeading = strings.Join(heading, " ")
+				}
+
+			case "td", "th":
+				// One entry per cell, even empty ones, so columns stay aligned
+				// with the header row.
+				if inCell {
+					currentRow = append(currentRow, strings.Join(currentCell, " "))
+				}
+				inCell = false
+
+			case "tr":
+				if inRow {
+					inRow = false
+					currentTable.Rows = append(currentTable.Rows, currentRow)
+				}
+
+			case "table":
+				if inTable {
+					inTable = false
+					tables = append(tables, currentTable)
+				}
+			}
+
+		case html.TextToken:
+			txt := strings.TrimSpace(z.Token().Data)
+			if txt == "" {
+				continue
+			}
+			switch {
+			case inCell:
+				currentCell = append(currentCell, txt)
+			case inCaption, inHeading:
+				heading = append(heading, txt)
+			}
+		}
+	}
+}
+
+// FindTable returns the first table whose label contains one of the keywords,
+// ignoring case.
+func FindTable(tables []Table, keywords ...string) (Table, bool) {
+	for _, t := range tables {
+		label := strings.ToLower(t.Label)
+		for _, k := range keywords {
+			if strings.Contains(label, k) {
+				return t, true
+			}
+		}
+	}
+	return Table{}, false
+}
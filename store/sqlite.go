@@ -0,0 +1,479 @@
+// Package store keeps every run's games in a database, so history
+// accumulates from run to run instead of each run overwriting the last.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"msLotto/model"
+	"msLotto/scrape"
+)
+
+// schema keys games and their tiers by game number and the run's start time.
+// The URL is part of the key too, for the odd page that shows no number.
+const schema = `
+CREATE TABLE IF NOT EXISTS runs (
+	started TEXT PRIMARY KEY, -- runTimeLayout
+	games   INTEGER NOT NULL,
+	run_id  TEXT -- NULL for runs recorded before run IDs
+);
+CREATE TABLE IF NOT EXISTS games (
+	game_number            INTEGER NOT NULL,
+	scraped_at             TEXT NOT NULL REFERENCES runs(started),
+	url                    TEXT NOT NULL,
+	name                   TEXT NOT NULL,
+	price                  INTEGER NOT NULL,
+	odds                   REAL NOT NULL,
+	launch_date            TEXT NOT NULL,
+	total_original_prizes  INTEGER NOT NULL,
+	total_remaining_prizes INTEGER NOT NULL,
+	total_tickets          INTEGER NOT NULL,
+	pack_size              INTEGER NOT NULL DEFAULT 0, -- 0 when the page doesn't list it
+	upc                    TEXT NOT NULL,
+	last_updated           TEXT, -- the site's own date, NULL when not shown
+	ev                     REAL NOT NULL,
+	parser_version         INTEGER, -- scrape.ParserVersion, NULL for rows recorded before versions
+	ended                  TEXT, -- RFC 3339, set on the final prize counts of a game that left the index this run
+	PRIMARY KEY (game_number, scraped_at, url)
+);
+CREATE TABLE IF NOT EXISTS prize_tiers (
+	game_number     INTEGER NOT NULL,
+	scraped_at      TEXT NOT NULL,
+	url             TEXT NOT NULL,
+	position        INTEGER NOT NULL, -- order on the page
+	value           INTEGER NOT NULL,
+	tag             TEXT NOT NULL,
+	original_count  INTEGER NOT NULL,
+	remaining_count INTEGER NOT NULL,
+	odds            REAL NOT NULL,
+	PRIMARY KEY (game_number, scraped_at, url, position),
+	FOREIGN KEY (game_number, scraped_at, url) REFERENCES games (game_number, scraped_at, url)
+);
+CREATE TABLE IF NOT EXISTS run_tags (
+	started TEXT NOT NULL REFERENCES runs(started),
+	tag     TEXT NOT NULL, -- e.g. "before-price-change", from scrape -tag or mslotto tag
+	PRIMARY KEY (started, tag)
+);
+`
+
+// runTimeLayout is how run start times are stored: RFC 3339 in UTC with a
+// fixed nine-digit fraction, so runs started within the same second get
+// keys of their own and the keys sort in time order.
+const runTimeLayout = "2006-01-02T15:04:05.000000000Z07:00"
+
+// Store is a database a run's games are recorded in.
+type Store interface {
+	SaveRun(started time.Time, runID string, games []model.Game) error
+	// TagRun labels the run started then, e.g. to mark it as the last
+	// before a price change. A tag the run already has is kept once.
+	TagRun(started time.Time, tags []string) error
+	Close() error
+}
+
+// SQLite is a history database in a single SQLite file.
+type SQLite struct {
+	db *sql.DB
+}
+
+// OpenSQLite opens the database at path, creating the file and tables on
+// first use.
+func OpenSQLite(path string) (*SQLite, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("creating tables in %s: %w", path, err)
+	}
+	for _, c := range []struct{ table, column, decl string }{
+		{"runs", "run_id", "TEXT"},
+		{"games", "parser_version", "INTEGER"},
+		{"games", "pack_size", "INTEGER NOT NULL DEFAULT 0"},
+		{"games", "ended", "TEXT"},
+	} {
+		if err := addColumn(db, c.table, c.column, c.decl); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("upgrading %s: %w", path, err)
+		}
+	}
+	if err := widenRunTimes(db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("upgrading %s: %w", path, err)
+	}
+	return &SQLite{db: db}, nil
+}
+
+// widenRunTimes rewrites run times recorded to the second, before
+// runTimeLayout, in that layout.
+func widenRunTimes(db *sql.DB) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, c := range []struct{ table, column string }{
+		{"runs", "started"},
+		{"games", "scraped_at"},
+		{"prize_tiers", "scraped_at"},
+	} {
+		_, err := tx.Exec(fmt.Sprintf(`UPDATE %[1]s SET %[2]s = substr(%[2]s, 1, 19) || '.000000000Z' WHERE length(%[2]s) = 20`, c.table, c.column))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// addColumn adds a column to a table created before the column existed.
+func addColumn(db *sql.DB, table, column, decl string) error {
+	rows, err := db.Query(`SELECT name FROM pragma_table_info(?)`, table)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	_, err = db.Exec(fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s %s`, table, column, decl))
+	return err
+}
+
+func (s *SQLite) Close() error {
+	return s.db.Close()
+}
+
+// SaveRun records one run and all of its games in a single transaction.
+// Games with Ended set are the ones that left the index this run, recorded
+// with their final prize counts.
+func (s *SQLite) SaveRun(started time.Time, runID string, games []model.Game) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	at := started.UTC().Format(runTimeLayout)
+	if _, err := tx.Exec(`INSERT INTO runs (started, games, run_id) VALUES (?, ?, ?)`, at, len(games), runID); err != nil {
+		return fmt.Errorf("recording run: %w", err)
+	}
+	for _, g := range games {
+		var updated, ended any
+		if !g.LastUpdated.IsZero() {
+			updated = g.LastUpdated.Format(time.RFC3339)
+		}
+		if !g.Ended.IsZero() {
+			ended = g.Ended.UTC().Format(time.RFC3339)
+		}
+		_, err := tx.Exec(`INSERT INTO games (game_number, scraped_at, url, name, price, odds, launch_date,
+			total_original_prizes, total_remaining_prizes, total_tickets, pack_size, upc, last_updated, ev, parser_version, ended)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			g.GameNumber, at, g.URL, g.Name, g.Price, g.Odds, g.LaunchDate,
+			g.TotalOriginalPrizes, g.TotalRemainingPrizes, g.TotalTickets, g.PackSize, g.UPC, updated, model.Round(g.EV(), 2), parserVersion(g), ended)
+		if err != nil {
+			return fmt.Errorf("recording %s: %w", g.Name, err)
+		}
+		for i, p := range g.PrizeTiers {
+			_, err := tx.Exec(`INSERT INTO prize_tiers (game_number, scraped_at, url, position, value, tag,
+				original_count, remaining_count, odds) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				g.GameNumber, at, g.URL, i, p.Value, p.Tag, p.OriginalCount, p.RemainingCount, p.Odds)
+			if err != nil {
+				return fmt.Errorf("recording %s prize tiers: %w", g.Name, err)
+			}
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) TagRun(started time.Time, tags []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	at := started.UTC().Format(runTimeLayout)
+	if err := tx.QueryRow(`SELECT 1 FROM runs WHERE started = ?`, at).Scan(new(int)); err == sql.ErrNoRows {
+		return fmt.Errorf("no run started at %s", at)
+	} else if err != nil {
+		return err
+	}
+	for _, tag := range tags {
+		if _, err := tx.Exec(`INSERT OR IGNORE INTO run_tags (started, tag) VALUES (?, ?)`, at, tag); err != nil {
+			return fmt.Errorf("tagging run: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+// parserVersion is the version recorded for g, NULL when it wasn't parsed
+// by a versioned parser.
+func parserVersion(g model.Game) any {
+	if g.ParserVersion == 0 {
+		return nil
+	}
+	return g.ParserVersion
+}
+
+// goodParsers is a condition leaving out games rows written by one of
+// scrape.BadParserVersions. Rows from before versions were recorded are kept.
+func goodParsers() string {
+	if len(scrape.BadParserVersions) == 0 {
+		return "1"
+	}
+	bad := make([]string, len(scrape.BadParserVersions))
+	for i, v := range scrape.BadParserVersions {
+		bad[i] = strconv.Itoa(v)
+	}
+	return "(parser_version IS NULL OR parser_version NOT IN (" + strings.Join(bad, ", ") + "))"
+}
+
+// Runs returns the start time of every recorded run, most recent first.
+func (s *SQLite) Runs() ([]time.Time, error) {
+	rows, err := s.db.Query(`SELECT started FROM runs ORDER BY started DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []time.Time
+	for rows.Next() {
+		var at string
+		if err := rows.Scan(&at); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(runTimeLayout, at)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, t)
+	}
+	return runs, rows.Err()
+}
+
+// Run is one recorded run.
+type Run struct {
+	Started time.Time
+	ID      string   // "" for runs recorded before run IDs
+	Tags    []string // in alphabetical order, see TagRun
+}
+
+// RunsBetween returns the runs started in [from, to), oldest first, with
+// their tags. A zero from or to leaves that end open.
+func (s *SQLite) RunsBetween(from, to time.Time) ([]Run, error) {
+	lo, hi := "", "\uffff"
+	if !from.IsZero() {
+		lo = from.UTC().Format(runTimeLayout)
+	}
+	if !to.IsZero() {
+		hi = to.UTC().Format(runTimeLayout)
+	}
+	rows, err := s.db.Query(`SELECT started, run_id, tag FROM runs LEFT JOIN run_tags USING (started)
+		WHERE started >= ? AND started < ? ORDER BY started, tag`, lo, hi)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []Run
+	for rows.Next() {
+		var at string
+		var id, tag sql.NullString
+		if err := rows.Scan(&at, &id, &tag); err != nil {
+			return nil, err
+		}
+		t, err := time.Parse(runTimeLayout, at)
+		if err != nil {
+			return nil, err
+		}
+		if len(runs) == 0 || !runs[len(runs)-1].Started.Equal(t) {
+			runs = append(runs, Run{Started: t, ID: id.String})
+		}
+		if tag.Valid {
+			runs[len(runs)-1].Tags = append(runs[len(runs)-1].Tags, tag.String)
+		}
+	}
+	return runs, rows.Err()
+}
+
+// Observation is a game as one run saw it.
+type Observation struct {
+	Scraped time.Time
+	Game    model.Game
+}
+
+// LoadRun reads back the games and prize tiers a run recorded, in game
+// number order, along with every game that had ended by then, as its final
+// prize counts with Ended set.
+func (s *SQLite) LoadRun(started time.Time) ([]model.Game, error) {
+	at := started.UTC().Format(runTimeLayout)
+	obs, err := s.queryGames(`(scraped_at = ? AND ended IS NULL OR ended IS NOT NULL AND scraped_at <= ?)
+		ORDER BY game_number, url, scraped_at`, at, at)
+	if err != nil {
+		return nil, err
+	}
+	// A game that ended, came back and ended again has an ended row for
+	// each time. Rows are in time order, so the last one for a game is the
+	// run's own or its latest ending.
+	var games []model.Game
+	for i, o := range obs {
+		if i+1 < len(obs) && obs[i+1].Game.GameNumber == o.Game.GameNumber && obs[i+1].Game.URL == o.Game.URL {
+			continue
+		}
+		games = append(games, o.Game)
+	}
+	return games, nil
+}
+
+// RunGames reads back only the rows a run itself wrote, in game number
+// order: the games it scraped, and the final prize counts of those that
+// ended that run. Unlike LoadRun it leaves out games that ended earlier,
+// so a range of runs lists each observation once.
+func (s *SQLite) RunGames(started time.Time) ([]model.Game, error) {
+	obs, err := s.queryGames(`scraped_at = ? ORDER BY game_number, url`, started.UTC().Format(runTimeLayout))
+	if err != nil {
+		return nil, err
+	}
+	games := make([]model.Game, len(obs))
+	for i, o := range obs {
+		games[i] = o.Game
+	}
+	return games, nil
+}
+
+// GameHistory returns every recorded observation of a game, oldest first,
+// ending with its final prize counts if it has left the index.
+func (s *SQLite) GameHistory(gameNumber int) ([]Observation, error) {
+	return s.queryGames(`game_number = ? ORDER BY scraped_at, url`, gameNumber)
+}
+
+// queryGames reads the games matching where, with their prize tiers.
+func (s *SQLite) queryGames(where string, args ...any) ([]Observation, error) {
+	rows, err := s.db.Query(`SELECT scraped_at, game_number, url, name, price, odds, launch_date,
+		total_original_prizes, total_remaining_prizes, total_tickets, pack_size, upc, last_updated, parser_version, ended
+		FROM games WHERE `+goodParsers()+` AND `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var obs []Observation
+	var ats []string
+	for rows.Next() {
+		var g model.Game
+		var at string
+		var updated, ended sql.NullString
+		var parser sql.NullInt64
+		if err := rows.Scan(&at, &g.GameNumber, &g.URL, &g.Name, &g.Price, &g.Odds, &g.LaunchDate,
+			&g.TotalOriginalPrizes, &g.TotalRemainingPrizes, &g.TotalTickets, &g.PackSize, &g.UPC, &updated, &parser, &ended); err != nil {
+			return nil, err
+		}
+		g.ParserVersion = int(parser.Int64)
+		if updated.Valid {
+			g.LastUpdated, _ = time.Parse(time.RFC3339, updated.String)
+		}
+		if ended.Valid {
+			g.Ended, _ = time.Parse(time.RFC3339, ended.String)
+		}
+		t, err := time.Parse(runTimeLayout, at)
+		if err != nil {
+			return nil, err
+		}
+		obs = append(obs, Observation{Scraped: t, Game: g})
+		ats = append(ats, at)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for i := range obs {
+		if err := s.loadTiers(&obs[i].Game, ats[i]); err != nil {
+			return nil, err
+		}
+	}
+	return obs, nil
+}
+
+// loadTiers fills in g's prize tiers as the run at recorded them.
+func (s *SQLite) loadTiers(g *model.Game, at string) error {
+	rows, err := s.db.Query(`SELECT value, tag, original_count, remaining_count, odds FROM prize_tiers
+		WHERE game_number = ? AND scraped_at = ? AND url = ? ORDER BY position`, g.GameNumber, at, g.URL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var p model.PrizeTier
+		if err := rows.Scan(&p.Value, &p.Tag, &p.OriginalCount, &p.RemainingCount, &p.Odds); err != nil {
+			return err
+		}
+		g.PrizeTiers = append(g.PrizeTiers, p)
+	}
+	return rows.Err()
+}
+
+// LastOdds returns each numbered game's overall odds as the latest run that
+// read them recorded them, for a page that is missing them.
+func (s *SQLite) LastOdds() (map[int]float64, error) {
+	rows, err := s.db.Query(`SELECT game_number, odds FROM games WHERE ` + goodParsers() + ` AND game_number != 0 AND odds > 0 ORDER BY scraped_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	odds := map[int]float64{}
+	for rows.Next() {
+		var number int
+		var o float64
+		if err := rows.Scan(&number, &o); err != nil {
+			return nil, err
+		}
+		odds[number] = o
+	}
+	return odds, rows.Err()
+}
+
+// RemainingPoint is one game's total remaining prizes in one run.
+type RemainingPoint struct {
+	GameNumber int
+	URL        string
+	Scraped    time.Time
+	Remaining  int
+}
+
+// RemainingHistory returns every game's remaining prize total per run,
+// oldest run first, without loading prize tiers. Ended games' final counts
+// are left out, as they weren't scraped at that run.
+func (s *SQLite) RemainingHistory() ([]RemainingPoint, error) {
+	rows, err := s.db.Query(`SELECT game_number, url, scraped_at, total_remaining_prizes FROM games WHERE ` + goodParsers() + ` AND ended IS NULL ORDER BY scraped_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var points []RemainingPoint
+	for rows.Next() {
+		var p RemainingPoint
+		var at string
+		if err := rows.Scan(&p.GameNumber, &p.URL, &at, &p.Remaining); err != nil {
+			return nil, err
+		}
+		if p.Scraped, err = time.Parse(runTimeLayout, at); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+	return points, rows.Err()
+}
@@ -0,0 +1,344 @@
+package store
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"msLotto/model"
+	"msLotto/scrape"
+)
+
+func openTestDB(t *testing.T) *SQLite {
+	t.Helper()
+	db, err := OpenSQLite(filepath.Join(t.TempDir(), "history.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func testGame(number, remaining int) model.Game {
+	return model.Game{
+		Name:       "Game",
+		Price:      5,
+		Odds:       3.5,
+		LaunchDate: "1/2/2025",
+		GameNumber: number,
+		PrizeTiers: []model.PrizeTier{
+			{Value: 1000, OriginalCount: 10, RemainingCount: 4, Odds: 30000},
+			{Value: 5, OriginalCount: 1000, RemainingCount: remaining, Tag: "FREE TICKET"},
+		},
+		TotalOriginalPrizes:  1010,
+		TotalRemainingPrizes: 4 + remaining,
+		TotalTickets:         3535,
+		PackSize:             150,
+		UPC:                  "012345678905",
+		LastUpdated:          time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		URL:                  "https://www.mslottery.com/games/game/",
+		ParserVersion:        scrape.ParserVersion,
+	}
+}
+
+func TestSQLiteRoundTrip(t *testing.T) {
+	db := openTestDB(t)
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	second := first.Add(24 * time.Hour)
+	if err := db.SaveRun(first, "run-1", []model.Game{testGame(2, 600), testGame(1, 900)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveRun(second, "run-2", []model.Game{testGame(1, 800)}); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := db.Runs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []time.Time{second, first}; !reflect.DeepEqual(runs, want) {
+		t.Errorf("Runs = %v, want %v", runs, want)
+	}
+
+	games, err := db.LoadRun(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []model.Game{testGame(1, 900), testGame(2, 600)}; !reflect.DeepEqual(games, want) {
+		t.Errorf("LoadRun =\n%+v\nwant\n%+v", games, want)
+	}
+
+	history, err := db.GameHistory(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || !history[0].Scraped.Equal(first) || !history[1].Scraped.Equal(second) {
+		t.Fatalf("GameHistory = %+v, want the two runs oldest first", history)
+	}
+	if got := history[1].Game.TotalRemainingPrizes; got != 804 {
+		t.Errorf("second observation has %d prizes left, want 804", got)
+	}
+
+	points, err := db.RemainingHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 3 || !points[2].Scraped.Equal(second) || points[2].Remaining != 804 {
+		t.Errorf("RemainingHistory = %+v", points)
+	}
+}
+
+func TestSQLiteSkipsBadParsers(t *testing.T) {
+	db := openTestDB(t)
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	old := testGame(1, 900)
+	old.ParserVersion = 0 // recorded before versions
+	bad := testGame(2, 900)
+	bad.ParserVersion = -1
+	if err := db.SaveRun(at, "run-1", []model.Game{old, bad, testGame(3, 900)}); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(v []int) { scrape.BadParserVersions = v }(scrape.BadParserVersions)
+	scrape.BadParserVersions = []int{-1}
+	games, err := db.LoadRun(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 || games[0].GameNumber != 1 || games[1].GameNumber != 3 {
+		t.Errorf("LoadRun kept %+v, want games 1 and 3", games)
+	}
+}
+
+func TestSQLiteReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := db.SaveRun(at, "run-1", []model.Game{testGame(1, 900)}); err != nil {
+		t.Fatal(err)
+	}
+	db.Close()
+
+	// Opening an existing file must not fail on tables and columns that
+	// are already there.
+	db, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	games, err := db.LoadRun(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 {
+		t.Errorf("reopened database has %d games, want 1", len(games))
+	}
+}
+
+func TestSQLiteUpgrade(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	db, err := OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A file from before pack sizes were recorded, and before run times
+	// had fractional seconds.
+	old := time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC)
+	if err := db.SaveRun(old, "run-0", []model.Game{testGame(1, 950)}); err != nil {
+		t.Fatal(err)
+	}
+	for _, q := range []string{
+		`ALTER TABLE games DROP COLUMN pack_size`,
+		`UPDATE runs SET started = '2025-02-01T12:00:00Z'`,
+		`UPDATE games SET scraped_at = '2025-02-01T12:00:00Z'`,
+		`UPDATE prize_tiers SET scraped_at = '2025-02-01T12:00:00Z'`,
+	} {
+		if _, err := db.db.Exec(q); err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	db, err = OpenSQLite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := db.SaveRun(at, "run-1", []model.Game{testGame(1, 900)}); err != nil {
+		t.Fatal(err)
+	}
+	games, err := db.LoadRun(at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 || games[0].PackSize != 150 {
+		t.Errorf("LoadRun after the upgrade = %+v, want pack size 150", games)
+	}
+	games, err = db.LoadRun(old)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 1 || games[0].TotalRemainingPrizes != 954 || len(games[0].PrizeTiers) != 2 {
+		t.Errorf("LoadRun of a run saved before the upgrade = %+v, want 954 remaining over 2 tiers", games)
+	}
+}
+
+func TestSQLiteSameSecond(t *testing.T) {
+	db := openTestDB(t)
+	first := time.Date(2025, 3, 1, 12, 0, 0, 100e6, time.UTC)
+	second := first.Add(500 * time.Millisecond)
+	if err := db.SaveRun(first, "run-1", []model.Game{testGame(1, 900)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveRun(second, "run-2", []model.Game{testGame(1, 800)}); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := db.Runs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 2 || !runs[0].Equal(second) || !runs[1].Equal(first) {
+		t.Errorf("Runs() = %v, want %v and %v", runs, second, first)
+	}
+	for at, want := range map[time.Time]int{first: 904, second: 804} {
+		games, err := db.LoadRun(at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(games) != 1 || games[0].TotalRemainingPrizes != want {
+			t.Errorf("LoadRun(%v) = %+v, want %d remaining", at, games, want)
+		}
+	}
+}
+
+func TestSQLiteEndedGames(t *testing.T) {
+	db := openTestDB(t)
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	second, third := first.Add(24*time.Hour), first.Add(48*time.Hour)
+	ended := testGame(2, 500)
+	ended.Ended = second
+	for _, run := range []struct {
+		at    time.Time
+		games []model.Game
+	}{
+		{first, []model.Game{testGame(1, 900), testGame(2, 600)}},
+		{second, []model.Game{testGame(1, 800), ended}},
+		{third, []model.Game{testGame(1, 700)}},
+	} {
+		if err := db.SaveRun(run.at, "", run.games); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Every run from the one it ended in on carries the game's final counts.
+	for _, at := range []time.Time{second, third} {
+		games, err := db.LoadRun(at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(games) != 2 || !games[0].Ended.IsZero() || !games[1].Ended.Equal(second) || games[1].TotalRemainingPrizes != 504 {
+			t.Errorf("LoadRun(%s) = %+v, want game 1 and game 2 ended", at, games)
+		}
+	}
+	history, err := db.GameHistory(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || !history[1].Game.Ended.Equal(second) {
+		t.Errorf("GameHistory = %+v, want two observations, the last ended", history)
+	}
+	points, err := db.RemainingHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 4 {
+		t.Errorf("RemainingHistory has %d points, want 4 without the ended game's", len(points))
+	}
+}
+
+func TestSQLiteRunsBetween(t *testing.T) {
+	db := openTestDB(t)
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	second, third := first.Add(24*time.Hour), first.Add(48*time.Hour)
+	ended := testGame(2, 500)
+	ended.Ended = second
+	for _, run := range []struct {
+		at    time.Time
+		id    string
+		games []model.Game
+	}{
+		{first, "", []model.Game{testGame(1, 900), testGame(2, 600)}},
+		{second, "run-2", []model.Game{testGame(1, 800), ended}},
+		{third, "run-3", []model.Game{testGame(1, 700)}},
+	} {
+		if err := db.SaveRun(run.at, run.id, run.games); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := db.TagRun(second, []string{"promo", "before-price-change", "promo"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.TagRun(first.Add(time.Hour), []string{"nope"}); err == nil {
+		t.Error("TagRun of a run never recorded succeeded")
+	}
+
+	runs, err := db.RunsBetween(second, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Run{{Started: second, ID: "run-2", Tags: []string{"before-price-change", "promo"}}, {Started: third, ID: "run-3"}}
+	if !reflect.DeepEqual(runs, want) {
+		t.Errorf("RunsBetween(second, open) = %v, want %v", runs, want)
+	}
+	runs, err = db.RunsBetween(time.Time{}, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Run{{Started: first}}; !reflect.DeepEqual(runs, want) {
+		t.Errorf("RunsBetween(open, second) = %v, want %v", runs, want)
+	}
+
+	// The ended game shows up in the run it ended in, and not after.
+	for _, tt := range []struct {
+		at   time.Time
+		want int
+	}{{second, 2}, {third, 1}} {
+		games, err := db.RunGames(tt.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(games) != tt.want {
+			t.Errorf("RunGames(%s) = %d games, want %d", tt.at, len(games), tt.want)
+		}
+	}
+}
+
+func TestSQLiteLastOdds(t *testing.T) {
+	db := openTestDB(t)
+	first := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	missing := testGame(1, 800)
+	missing.Odds = 0
+	changed := testGame(2, 600)
+	changed.Odds = 4.1
+	if err := db.SaveRun(first, "", []model.Game{testGame(1, 900), testGame(2, 600)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SaveRun(first.Add(time.Hour), "", []model.Game{missing, changed}); err != nil {
+		t.Fatal(err)
+	}
+	odds, err := db.LastOdds()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Game 1's latest run read no odds, so its earlier ones stand.
+	if want := map[int]float64{1: 3.5, 2: 4.1}; !reflect.DeepEqual(odds, want) {
+		t.Errorf("LastOdds = %v, want %v", odds, want)
+	}
+}